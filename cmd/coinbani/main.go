@@ -4,12 +4,26 @@ import (
 	"context"
 	"fmt"
 	"log"
+	// the alpine image has no zoneinfo, digests need the chat timezones
+	_ "time/tzdata"
 
 	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/alert"
+	"coinbani/pkg/arbitrage"
+	"coinbani/pkg/cache"
+	"coinbani/pkg/chart"
 	"coinbani/pkg/client"
+	"coinbani/pkg/compare"
+	"coinbani/pkg/convert"
+	"coinbani/pkg/cryptodollar"
 	"coinbani/pkg/currency"
 	"coinbani/pkg/currency/provider"
+	"coinbani/pkg/digest"
+	"coinbani/pkg/gap"
+	"coinbani/pkg/history"
 	"coinbani/pkg/reply"
+	"coinbani/pkg/store"
+	"coinbani/pkg/tax"
 	"coinbani/pkg/telegram"
 	"coinbani/pkg/template"
 
@@ -18,8 +32,11 @@ import (
 )
 
 func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	var cfg options.Config
-	if err := envconfig.Process(context.Background(), &cfg); err != nil {
+	if err := envconfig.Process(ctx, &cfg); err != nil {
 		log.Fatal(err)
 	}
 
@@ -39,14 +56,78 @@ func main() {
 	}
 
 	// setup services
-	restClient := client.NewRestClient()
+	restClient := client.NewRestClient(cfg.Client, logger)
 	bbProvider := provider.NewBBProvider(cfg.Providers, restClient)
 	satoshiTProvider := provider.NewSatoshiTProvider(cfg.Providers, restClient)
-	dollarProvider := provider.NewDollarProvider(cfg.Providers, restClient)
+	ripioProvider := provider.NewRipioProvider(cfg.Providers, restClient)
+	binanceP2PProvider := provider.NewBinanceP2PProvider(cfg.Providers, restClient)
+	taxRules, err := tax.Load(cfg.Taxes.RulesPath)
+	if err != nil {
+		logger.Fatal("loading tax rules", zap.Error(err))
+	}
+	dollarProvider := provider.NewDollarProvider(cfg.Providers, restClient, taxRules)
+
+	pricesCache := cache.New()
+	providerRegistry := currency.NewRegistry()
+	err = providerRegistry.Register(
+		currency.NewCachedProvider(bbProvider, pricesCache, cfg.Providers.BBCacheTTL, cfg.Providers, logger),
+		currency.NewCachedProvider(satoshiTProvider, pricesCache, cfg.Providers.SatoshiTCacheTTL, cfg.Providers, logger),
+		currency.NewCachedProvider(ripioProvider, pricesCache, cfg.Providers.RipioCacheTTL, cfg.Providers, logger),
+		currency.NewCachedProvider(binanceP2PProvider, pricesCache, cfg.Providers.BinanceP2PCacheTTL, cfg.Providers, logger),
+		currency.NewCachedProvider(dollarProvider, pricesCache, cfg.Providers.DollarCacheTTL, cfg.Providers, logger),
+	)
+	if err != nil {
+		logger.Fatal("registering currency providers", zap.Error(err))
+	}
 
-	currencyService := currency.NewService(bbProvider, satoshiTProvider, dollarProvider, logger)
+	currencyService := currency.NewService(cfg.Providers, providerRegistry, logger)
 	templateEngine := template.NewEngine()
-	replyHandler := reply.NewHandler(bot, currencyService, templateEngine, logger)
+
+	stateStore, err := store.New(cfg.Store)
+	if err != nil {
+		logger.Fatal("opening store", zap.Error(err))
+	}
+	defer stateStore.Close()
+
+	alertService := alert.NewService(cfg.Alerts, stateStore, currencyService, logger)
+	alertScheduler := alert.NewScheduler(cfg.Alerts, stateStore, currencyService, bot, templateEngine, logger)
+	go alertScheduler.Run(ctx)
+
+	arbitrageService := arbitrage.NewService(cfg.Arbitrage, currencyService, stateStore)
+	arbitrageScheduler := arbitrage.NewScheduler(cfg.Arbitrage, stateStore, arbitrageService, bot, templateEngine, logger)
+	go arbitrageScheduler.Run(ctx)
+
+	digestService := digest.NewService(cfg.Digest, stateStore, currencyService)
+	digestScheduler := digest.NewScheduler(cfg.Digest, stateStore, currencyService, bot, templateEngine, logger)
+	go digestScheduler.Run(ctx)
+
+	historyRecorder := history.NewRecorder(cfg.History, stateStore, currencyService, logger)
+	go historyRecorder.Run(ctx)
+	historyService := history.NewService(stateStore, currencyService)
+	gapService := gap.NewService(stateStore, currencyService, provider.DollarProviderLabel)
+	cryptoDollarService := cryptodollar.NewService(currencyService, provider.DollarProviderLabel, []string{provider.MEPDollarPair, provider.CCLDollarPair, provider.BlueDollarPair})
+
+	compareService := compare.NewService(currencyService)
+	convertService := convert.NewService(cfg.Convert, currencyService)
+	taxService := tax.NewService(taxRules, currencyService, provider.DollarProviderLabel, provider.OfficialDollarPair)
+
+	replyHandler := reply.NewHandler(cfg.Bot, &reply.Deps{
+		Bot:                 bot,
+		UserStore:           stateStore,
+		CurrencyService:     currencyService,
+		AlertService:        alertService,
+		CompareService:      compareService,
+		ArbitrageService:    arbitrageService,
+		ConvertService:      convertService,
+		DigestService:       digestService,
+		HistoryService:      historyService,
+		TaxService:          taxService,
+		GapService:          gapService,
+		CryptoDollarService: cryptoDollarService,
+		ChartRenderer:       chart.NewRenderer(),
+		TemplateEngine:      templateEngine,
+		StatusProvider:      restClient,
+	}, logger)
 
 	logger.Info("coinbani bot successfully started!")
 
@@ -58,7 +139,7 @@ func main() {
 				break
 			}
 
-			go replyHandler.HandleReply(update)
+			go replyHandler.HandleReply(ctx, update)
 		}
 	}
 }
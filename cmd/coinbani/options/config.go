@@ -2,12 +2,32 @@ package options
 
 import (
 	"encoding/json"
+	"time"
 )
 
 type Config struct {
+	Alerts    *AlertsConfig
+	Arbitrage *ArbitrageConfig
 	Bot       *BotConfig
+	Client    *ClientConfig
+	Convert   *ConvertConfig
+	Digest    *DigestConfig
+	History   *HistoryConfig
 	Log       *LogConfig
 	Providers *ProvidersConfig
+	Store     *StoreConfig
+	Taxes     *TaxesConfig
+}
+
+type AlertsConfig struct {
+	CheckInterval time.Duration `env:"ALERTS_CHECK_INTERVAL,default=1m"`
+	MaxPerChat    int           `env:"ALERTS_MAX_PER_CHAT,default=10"`
+}
+
+type ArbitrageConfig struct {
+	CheckInterval time.Duration `env:"ARBITRAGE_CHECK_INTERVAL,default=5m"`
+	MaxHops       int           `env:"ARBITRAGE_MAX_HOPS,default=4"`
+	MaxResults    int           `env:"ARBITRAGE_MAX_RESULTS,default=5"`
 }
 
 type BotConfig struct {
@@ -16,14 +36,92 @@ type BotConfig struct {
 	Port             string `env:"PORT"`
 	Token            string `env:"BOT_TOKEN,required"`
 	IsWebhookEnabled bool   `env:"BOT_IS_WEBHOOK_ENABLED,default=false"`
+	// AdminChatIDs are the chats allowed to run admin commands such as /estado
+	AdminChatIDs []int64 `env:"BOT_ADMIN_CHAT_IDS"`
+}
+
+type ClientConfig struct {
+	// BreakerFailureThreshold is the number of consecutive failures opening the circuit of a host, 0 disables it
+	BreakerFailureThreshold int           `env:"HTTP_BREAKER_FAILURE_THRESHOLD,default=5"`
+	BreakerOpenTimeout      time.Duration `env:"HTTP_BREAKER_OPEN_TIMEOUT,default=30s"`
+	BreakerHalfOpenRequests int           `env:"HTTP_BREAKER_HALF_OPEN_REQUESTS,default=1"`
+	// MaxResponseSize is the maximum response body size in bytes, 0 disables the limit
+	MaxResponseSize int64 `env:"HTTP_MAX_RESPONSE_SIZE,default=1048576"`
 }
 
 type ProvidersConfig struct {
-	BBURL           string  `env:"BB_URL"`
-	DollarURL       string  `env:"DOLLAR_URL"`
-	DollarSavingTax float64 `env:"DOLLAR_SAVING_TAX"`
-	SatoshiARSURL   string  `env:"SATOSHI_ARS_URL"`
-	SatoshiUSDURL   string  `env:"SATOSHI_USD_URL"`
+	BBURL                   string        `env:"BB_URL"`
+	BBEnabled               bool          `env:"BB_ENABLED,default=true"`
+	BBCacheTTL              time.Duration `env:"BB_CACHE_TTL,default=1m"`
+	BBTimeout               time.Duration `env:"BB_TIMEOUT,default=5s"`
+	BBRetryAttempts         int           `env:"BB_RETRY_ATTEMPTS,default=3"`
+	BBFees                  FeeModel      `env:"BB_FEES"`
+	BinanceP2PURL           string        `env:"BINANCE_P2P_URL"`
+	BinanceP2PEnabled       bool          `env:"BINANCE_P2P_ENABLED,default=true"`
+	BinanceP2PCacheTTL      time.Duration `env:"BINANCE_P2P_CACHE_TTL,default=1m"`
+	BinanceP2PTimeout       time.Duration `env:"BINANCE_P2P_TIMEOUT,default=5s"`
+	BinanceP2PRetryAttempts int           `env:"BINANCE_P2P_RETRY_ATTEMPTS,default=3"`
+	BinanceP2PFees          FeeModel      `env:"BINANCE_P2P_FEES"`
+	BinanceP2PTopAds        int           `env:"BINANCE_P2P_TOP_ADS,default=10"`     // best ads the price is the median of
+	BinanceP2PMinVolume     float64       `env:"BINANCE_P2P_MIN_VOLUME,default=100"` // USDT an ad must have available
+	BinanceP2PMerchantOnly  bool          `env:"BINANCE_P2P_MERCHANT_ONLY,default=true"`
+	DollarURL               string        `env:"DOLLAR_URL"`
+	DollarEnabled           bool          `env:"DOLLAR_ENABLED,default=true"`
+	DollarCacheTTL          time.Duration `env:"DOLLAR_CACHE_TTL,default=5m"`
+	DollarTimeout           time.Duration `env:"DOLLAR_TIMEOUT,default=5s"`
+	DollarRetryAttempts     int           `env:"DOLLAR_RETRY_ATTEMPTS,default=3"`
+	DollarFees              FeeModel      `env:"DOLLAR_FEES"`
+	RipioURL                string        `env:"RIPIO_URL"`
+	RipioEnabled            bool          `env:"RIPIO_ENABLED,default=true"`
+	RipioCacheTTL           time.Duration `env:"RIPIO_CACHE_TTL,default=1m"`
+	RipioTimeout            time.Duration `env:"RIPIO_TIMEOUT,default=5s"`
+	RipioRetryAttempts      int           `env:"RIPIO_RETRY_ATTEMPTS,default=3"`
+	RipioFees               FeeModel      `env:"RIPIO_FEES"`
+	SatoshiARSURL           string        `env:"SATOSHI_ARS_URL"`
+	SatoshiUSDURL           string        `env:"SATOSHI_USD_URL"`
+	SatoshiTEnabled         bool          `env:"SATOSHI_ENABLED,default=true"`
+	SatoshiTCacheTTL        time.Duration `env:"SATOSHI_CACHE_TTL,default=1m"`
+	SatoshiTTimeout         time.Duration `env:"SATOSHI_TIMEOUT,default=5s"`
+	SatoshiTRetryAttempts   int           `env:"SATOSHI_RETRY_ATTEMPTS,default=3"`
+	SatoshiTFees            FeeModel      `env:"SATOSHI_FEES,default=spread=1"`
+
+	FetchTimeout         time.Duration `env:"PROVIDERS_FETCH_TIMEOUT,default=8s"`
+	StaleWhileRevalidate time.Duration `env:"PROVIDERS_STALE_WHILE_REVALIDATE,default=1m"`
+	StaleIfError         time.Duration `env:"PROVIDERS_STALE_IF_ERROR,default=1h"`
+
+	RetryInitialBackoff time.Duration `env:"PROVIDERS_RETRY_INITIAL_BACKOFF,default=200ms"`
+	RetryMaxBackoff     time.Duration `env:"PROVIDERS_RETRY_MAX_BACKOFF,default=2s"`
+	RetryMultiplier     float64       `env:"PROVIDERS_RETRY_MULTIPLIER,default=2"`
+	RetryJitter         float64       `env:"PROVIDERS_RETRY_JITTER,default=0.2"`
+	RetryStatusCodes    []int         `env:"PROVIDERS_RETRY_STATUS_CODES"`
+}
+
+type StoreConfig struct {
+	// Driver is either bolt, for a file backed store, or memory
+	Driver string `env:"STORE_DRIVER,default=bolt"`
+	Path   string `env:"STORE_PATH,default=coinbani.db"`
+}
+
+type ConvertConfig struct {
+	MaxHops int `env:"CONVERT_MAX_HOPS,default=3"`
+}
+
+type TaxesConfig struct {
+	// RulesPath is the JSON file with the tax rules, the embedded defaults are used when empty
+	RulesPath string `env:"TAXES_RULES_PATH"`
+}
+
+type DigestConfig struct {
+	CheckInterval   time.Duration `env:"DIGEST_CHECK_INTERVAL,default=1m"`
+	DefaultTimezone string        `env:"DIGEST_DEFAULT_TIMEZONE,default=America/Argentina/Buenos_Aires"`
+	// MaxDelay is how late a digest can still be delivered, e.g. after a restart
+	MaxDelay time.Duration `env:"DIGEST_MAX_DELAY,default=1h"`
+}
+
+type HistoryConfig struct {
+	RecordInterval time.Duration `env:"HISTORY_RECORD_INTERVAL,default=5m"`
+	// Retention is how long price snapshots are kept, never less than 30 days
+	Retention time.Duration `env:"HISTORY_RETENTION,default=720h"`
 }
 
 type LogConfig struct {
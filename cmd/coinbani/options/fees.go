@@ -0,0 +1,55 @@
+package options
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FeeModel holds the fees charged by a provider as percents, configured as
+// "taker=0.5;withdrawal=0.1;spread=1" with the missing fees being zero.
+type FeeModel struct {
+	// TakerPercent and SpreadPercent are charged on the quotes, WithdrawalPercent when moving funds out
+	TakerPercent      float64
+	WithdrawalPercent float64
+	// SpreadPercent is a fixed markup over the quotes on both sides
+	SpreadPercent float64
+}
+
+func (f *FeeModel) EnvDecode(val string) error {
+	*f = FeeModel{}
+	for _, item := range strings.Split(val, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid fee %q, expected name=percent", item)
+		}
+
+		percent, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || percent < 0 {
+			return fmt.Errorf("invalid fee percent %q", item)
+		}
+
+		switch strings.TrimSpace(parts[0]) {
+		case "taker":
+			f.TakerPercent = percent
+		case "withdrawal":
+			f.WithdrawalPercent = percent
+		case "spread":
+			f.SpreadPercent = percent
+		default:
+			return fmt.Errorf("unknown fee %q", parts[0])
+		}
+	}
+
+	return nil
+}
+
+// IsZero reports whether the provider charges no fees.
+func (f FeeModel) IsZero() bool {
+	return f == FeeModel{}
+}
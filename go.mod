@@ -1,19 +1,25 @@
 module coinbani
 
-go 1.12
+go 1.18
 
 require (
 	github.com/alexeyco/simpletable v0.0.0-20200203113705-55bd62a5b8df
 	github.com/go-telegram-bot-api/telegram-bot-api v4.6.4+incompatible
-	github.com/mattn/go-runewidth v0.0.9 // indirect
 	github.com/pkg/errors v0.9.1
 	github.com/sethvargo/go-envconfig v0.2.2
-	github.com/stretchr/testify v1.5.1 // indirect
-	github.com/technoweenie/multipartstreamer v1.0.1 // indirect
+	github.com/shopspring/decimal v1.4.0
+	go.etcd.io/bbolt v1.3.8
 	go.uber.org/zap v1.15.0
+)
+
+require (
+	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/stretchr/testify v1.8.1 // indirect
+	github.com/technoweenie/multipartstreamer v1.0.1 // indirect
+	go.uber.org/atomic v1.6.0 // indirect
+	go.uber.org/multierr v1.5.0 // indirect
 	golang.org/x/lint v0.0.0-20200302205851-738671d3881b // indirect
-	golang.org/x/mod v0.3.0 // indirect
+	golang.org/x/sys v0.4.0 // indirect
 	golang.org/x/tools v0.0.0-20200529172331-a64b76657301 // indirect
-	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/yaml.v2 v2.2.8 // indirect
 )
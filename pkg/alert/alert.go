@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+type Operator string
+
+const (
+	OperatorAbove Operator = ">"
+	OperatorBelow Operator = "<"
+)
+
+var ErrInvalidFormat = errors.New("invalid alert format")
+
+// Alert is triggered when the ask price of a pair crosses the value. Percent alerts
+// compare the change against the reference price taken when the alert was created.
+type Alert struct {
+	ID             int64
+	ChatID         int64
+	Pair           string
+	Provider       string
+	Operator       Operator
+	Value          currency.Money
+	Percent        bool
+	ReferencePrice currency.Money
+	CreatedAt      time.Time
+}
+
+// IsTriggered reports whether the given price meets the alert condition.
+func (a *Alert) IsTriggered(price currency.Money) bool {
+	v := price
+	if a.Percent {
+		if a.ReferencePrice.IsZero() {
+			return false
+		}
+		v = currency.PercentChange(a.ReferencePrice, price)
+	}
+
+	switch a.Operator {
+	case OperatorAbove:
+		return v.GreaterThan(a.Value)
+	case OperatorBelow:
+		return v.LessThan(a.Value)
+	default:
+		return false
+	}
+}
+
+// Condition returns the alert condition as typed by the user, e.g. "> 130.00" or "< -5.00%".
+func (a *Alert) Condition() string {
+	if a.Percent {
+		return fmt.Sprintf("%s %s%%", a.Operator, a.Value.StringFixed(2))
+	}
+	return fmt.Sprintf("%s %s", a.Operator, a.Value.StringFixed(2))
+}
+
+// Parse parses the arguments of the alert command: <par> <proveedor> <operador> <valor>.
+// The provider can contain spaces and the value can be a percent change, e.g. "5%".
+func Parse(args string) (*Alert, error) {
+	fields := strings.Fields(args)
+	if len(fields) < 4 {
+		return nil, ErrInvalidFormat
+	}
+
+	n := len(fields)
+	op := Operator(fields[n-2])
+	if op != OperatorAbove && op != OperatorBelow {
+		return nil, errors.Wrapf(ErrInvalidFormat, "unknown operator %s", op)
+	}
+
+	rawValue := fields[n-1]
+	percent := strings.HasSuffix(rawValue, "%")
+	rawValue = strings.Replace(strings.TrimSuffix(rawValue, "%"), ",", ".", 1)
+	value, err := currency.ParseMoney(rawValue)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidFormat, "invalid value %s", fields[n-1])
+	}
+
+	return &Alert{
+		Pair:     fields[0],
+		Provider: strings.Join(fields[1:n-2], " "),
+		Operator: op,
+		Value:    value,
+		Percent:  percent,
+	}, nil
+}
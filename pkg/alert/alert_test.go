@@ -0,0 +1,114 @@
+package alert
+
+import (
+	"reflect"
+	"testing"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    *Alert
+		wantErr error
+	}{
+		{
+			name: "price above",
+			args: "Blue Dolar > 150",
+			want: &Alert{Pair: "Blue", Provider: "Dolar", Operator: OperatorAbove, Value: currency.MustParseMoney("150")},
+		},
+		{
+			name: "provider with spaces and decimal comma",
+			args: "DAI/ARS Satoshi Tango < 130,5",
+			want: &Alert{Pair: "DAI/ARS", Provider: "Satoshi Tango", Operator: OperatorBelow, Value: currency.MustParseMoney("130.5")},
+		},
+		{
+			name: "percent change",
+			args: "BTC/ARS Buenbit < -5%",
+			want: &Alert{Pair: "BTC/ARS", Provider: "Buenbit", Operator: OperatorBelow, Value: currency.MustParseMoney("-5"), Percent: true},
+		},
+		{
+			name:    "missing value",
+			args:    "Blue Dolar >",
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "unknown operator",
+			args:    "Blue Dolar = 150",
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "invalid value",
+			args:    "Blue Dolar > mucho",
+			wantErr: ErrInvalidFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args)
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlert_IsTriggered(t *testing.T) {
+	tests := []struct {
+		name  string
+		alert *Alert
+		price currency.Money
+		want  bool
+	}{
+		{
+			name:  "price above threshold",
+			alert: &Alert{Operator: OperatorAbove, Value: currency.MustParseMoney("150")},
+			price: currency.MustParseMoney("151"),
+			want:  true,
+		},
+		{
+			name:  "price not above threshold",
+			alert: &Alert{Operator: OperatorAbove, Value: currency.MustParseMoney("150")},
+			price: currency.MustParseMoney("150"),
+			want:  false,
+		},
+		{
+			name:  "price below threshold",
+			alert: &Alert{Operator: OperatorBelow, Value: currency.MustParseMoney("150")},
+			price: currency.MustParseMoney("149.99"),
+			want:  true,
+		},
+		{
+			name:  "percent rise",
+			alert: &Alert{Operator: OperatorAbove, Value: currency.MustParseMoney("5"), Percent: true, ReferencePrice: currency.MustParseMoney("100")},
+			price: currency.MustParseMoney("106"),
+			want:  true,
+		},
+		{
+			name:  "percent drop not reached",
+			alert: &Alert{Operator: OperatorBelow, Value: currency.MustParseMoney("-5"), Percent: true, ReferencePrice: currency.MustParseMoney("100")},
+			price: currency.MustParseMoney("96"),
+			want:  false,
+		},
+		{
+			name:  "percent without reference price",
+			alert: &Alert{Operator: OperatorAbove, Value: currency.MustParseMoney("5"), Percent: true},
+			price: currency.MustParseMoney("106"),
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.alert.IsTriggered(tt.price); got != tt.want {
+				t.Errorf("IsTriggered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
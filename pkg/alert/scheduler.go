@@ -0,0 +1,110 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	tb "github.com/go-telegram-bot-api/telegram-bot-api"
+	"go.uber.org/zap"
+)
+
+type notifier interface {
+	Send(c tb.Chattable) (tb.Message, error)
+}
+
+type templateEngine interface {
+	FormatAlertTriggeredMessage(a *Alert, price currency.Money) (string, error)
+}
+
+// scheduler periodically evaluates every active alert, notifying and removing the triggered ones.
+type scheduler struct {
+	config          *options.AlertsConfig
+	repository      repository
+	currencyService currencyService
+	notifier        notifier
+	templateEngine  templateEngine
+	logger          *zap.Logger
+}
+
+func NewScheduler(c *options.AlertsConfig, r repository, cs currencyService, n notifier, t templateEngine, l *zap.Logger) *scheduler {
+	return &scheduler{
+		config:          c,
+		repository:      r,
+		currencyService: cs,
+		notifier:        n,
+		templateEngine:  t,
+		logger:          l,
+	}
+}
+
+// Run evaluates the alerts every check interval until the context is done.
+func (s *scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.check(ctx)
+		}
+	}
+}
+
+func (s *scheduler) check(ctx context.Context) {
+	alerts, err := s.repository.ListAlerts()
+	if err != nil {
+		s.logger.Error("listing alerts", zap.Error(err))
+		return
+	}
+
+	// fetch each provider once
+	byProvider := make(map[string][]*Alert)
+	for _, a := range alerts {
+		byProvider[a.Provider] = append(byProvider[a.Provider], a)
+	}
+
+	for providerName, providerAlerts := range byProvider {
+		priceList, err := s.currencyService.GetLastPrices(ctx, providerName)
+		if err != nil {
+			s.logger.Error("getting prices for alerts", zap.String("provider", providerName), zap.Error(err))
+			continue
+		}
+		// an alert must not fire on prices the provider no longer quotes
+		if priceList.IsStale() {
+			s.logger.Warn("skipping stale prices for alerts", zap.String("provider", providerName))
+			continue
+		}
+
+		for _, a := range providerAlerts {
+			price := priceList.Find(a.Pair)
+			if price == nil || !a.IsTriggered(price.AskPrice) {
+				continue
+			}
+			s.notify(a, price.AskPrice)
+		}
+	}
+}
+
+func (s *scheduler) notify(a *Alert, price currency.Money) {
+	text, err := s.templateEngine.FormatAlertTriggeredMessage(a, price)
+	if err != nil {
+		s.logger.Error("formatting alert message", zap.Int64("alertID", a.ID), zap.Error(err))
+		return
+	}
+
+	msg := tb.NewMessage(a.ChatID, text)
+	msg.ParseMode = tb.ModeHTML
+	if _, err := s.notifier.Send(msg); err != nil {
+		s.logger.Error("sending alert notification", zap.Int64("alertID", a.ID), zap.Int64("chatID", a.ChatID), zap.Error(err))
+		return
+	}
+
+	if err := s.repository.DeleteAlert(a.ChatID, a.ID); err != nil {
+		s.logger.Error("deleting triggered alert", zap.Int64("alertID", a.ID), zap.Error(err))
+	}
+}
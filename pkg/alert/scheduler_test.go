@@ -0,0 +1,141 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	tb "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type fakeRepository struct {
+	alerts  []*Alert
+	deleted []int64
+}
+
+func (r *fakeRepository) AddAlert(a *Alert) error {
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+func (r *fakeRepository) ListAlerts() ([]*Alert, error) {
+	return r.alerts, nil
+}
+
+func (r *fakeRepository) ListChatAlerts(chatID int64) ([]*Alert, error) {
+	var alerts []*Alert
+	for _, a := range r.alerts {
+		if a.ChatID == chatID {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *fakeRepository) DeleteAlert(chatID int64, id int64) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type fakeCurrencyService map[string]*currency.CurrencyPriceList
+
+func (f fakeCurrencyService) ProviderByName(name string) (currency.ProviderInfo, bool) {
+	_, found := f[name]
+	return currency.ProviderInfo{Label: name}, found
+}
+
+func (f fakeCurrencyService) GetLastPrices(ctx context.Context, providerName string) (*currency.CurrencyPriceList, error) {
+	l, found := f[providerName]
+	if !found {
+		return nil, errors.New("upstream error")
+	}
+	return l, nil
+}
+
+type fakeNotifier struct {
+	sent []string
+	err  error
+}
+
+func (n *fakeNotifier) Send(c tb.Chattable) (tb.Message, error) {
+	if n.err != nil {
+		return tb.Message{}, n.err
+	}
+	msg := c.(tb.MessageConfig)
+	n.sent = append(n.sent, fmt.Sprintf("%d: %s", msg.ChatID, msg.Text))
+	return tb.Message{}, nil
+}
+
+type fakeTemplateEngine struct{}
+
+func (fakeTemplateEngine) FormatAlertTriggeredMessage(a *Alert, price currency.Money) (string, error) {
+	return fmt.Sprintf("%s %s", a.Pair, price), nil
+}
+
+func Test_scheduler_check(t *testing.T) {
+	prices := func(status currency.CacheStatus, blue string) *currency.CurrencyPriceList {
+		return &currency.CurrencyPriceList{
+			CacheStatus: status,
+			Prices:      []*currency.CurrencyPrice{{Desc: "Blue", AskPrice: currency.MustParseMoney(blue)}},
+		}
+	}
+	alerts := []*Alert{
+		{ID: 1, ChatID: 10, Pair: "Blue", Provider: "Dolar", Operator: OperatorAbove, Value: currency.MustParseMoney("150")},
+		{ID: 2, ChatID: 20, Pair: "Blue", Provider: "Dolar", Operator: OperatorBelow, Value: currency.MustParseMoney("100")},
+		{ID: 3, ChatID: 30, Pair: "Blue", Provider: "Dolar", Operator: OperatorAbove, Value: currency.MustParseMoney("10"), Percent: true, ReferencePrice: currency.MustParseMoney("100")},
+	}
+
+	tests := []struct {
+		name        string
+		prices      fakeCurrencyService
+		notifyErr   error
+		wantSent    []string
+		wantDeleted []int64
+	}{
+		{
+			name:        "crossed thresholds",
+			prices:      fakeCurrencyService{"Dolar": prices(currency.CacheStatusHit, "160")},
+			wantSent:    []string{"10: Blue 160", "30: Blue 160"},
+			wantDeleted: []int64{1, 3},
+		},
+		{
+			name:   "thresholds not crossed",
+			prices: fakeCurrencyService{"Dolar": prices(currency.CacheStatusMiss, "105")},
+		},
+		{
+			name:   "stale prices",
+			prices: fakeCurrencyService{"Dolar": prices(currency.CacheStatusStale, "160")},
+		},
+		{
+			name:   "provider down",
+			prices: fakeCurrencyService{},
+		},
+		{
+			name:      "failed notification keeps the alert",
+			prices:    fakeCurrencyService{"Dolar": prices(currency.CacheStatusHit, "90")},
+			notifyErr: errors.New("telegram error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{alerts: alerts}
+			n := &fakeNotifier{err: tt.notifyErr}
+			s := NewScheduler(&options.AlertsConfig{}, r, tt.prices, n, fakeTemplateEngine{}, zap.NewNop())
+
+			s.check(context.Background())
+
+			if !reflect.DeepEqual(n.sent, tt.wantSent) {
+				t.Errorf("check() sent %v, want %v", n.sent, tt.wantSent)
+			}
+			if !reflect.DeepEqual(r.deleted, tt.wantDeleted) {
+				t.Errorf("check() deleted %v, want %v", r.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotFound      = errors.New("alert not found")
+	ErrUnknownPair   = errors.New("unknown pair")
+	ErrTooManyAlerts = errors.New("too many alerts")
+)
+
+type currencyService interface {
+	ProviderByName(name string) (currency.ProviderInfo, bool)
+	GetLastPrices(ctx context.Context, providerName string) (*currency.CurrencyPriceList, error)
+}
+
+type repository interface {
+	AddAlert(a *Alert) error
+	ListAlerts() ([]*Alert, error)
+	ListChatAlerts(chatID int64) ([]*Alert, error)
+	// DeleteAlert fails with ErrNotFound when the chat has no alert with the given id
+	DeleteAlert(chatID int64, id int64) error
+}
+
+type service struct {
+	config          *options.AlertsConfig
+	repository      repository
+	currencyService currencyService
+	logger          *zap.Logger
+}
+
+func NewService(c *options.AlertsConfig, r repository, cs currencyService, l *zap.Logger) *service {
+	return &service{config: c, repository: r, currencyService: cs, logger: l}
+}
+
+// Create parses the alert command arguments and stores the alert for the chat.
+func (s *service) Create(ctx context.Context, chatID int64, args string) (*Alert, error) {
+	a, err := Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.repository.ListChatAlerts(chatID)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing chat alerts")
+	}
+	if len(alerts) >= s.config.MaxPerChat {
+		return nil, ErrTooManyAlerts
+	}
+
+	p, found := s.currencyService.ProviderByName(a.Provider)
+	if !found {
+		return nil, currency.ErrUnknownProvider
+	}
+
+	priceList, err := s.currencyService.GetLastPrices(ctx, p.Label)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting reference price")
+	}
+
+	price := priceList.Find(a.Pair)
+	if price == nil {
+		return nil, ErrUnknownPair
+	}
+
+	a.ChatID = chatID
+	a.Pair = price.Desc
+	a.Provider = p.Label
+	a.ReferencePrice = price.AskPrice
+	a.CreatedAt = time.Now()
+
+	if err := s.repository.AddAlert(a); err != nil {
+		return nil, errors.Wrap(err, "storing alert")
+	}
+
+	return a, nil
+}
+
+func (s *service) List(chatID int64) ([]*Alert, error) {
+	return s.repository.ListChatAlerts(chatID)
+}
+
+func (s *service) Delete(chatID int64, id int64) error {
+	return s.repository.DeleteAlert(chatID, id)
+}
@@ -0,0 +1,129 @@
+package arbitrage
+
+import (
+	"sort"
+	"strings"
+
+	"coinbani/pkg/currency"
+	"coinbani/pkg/market"
+)
+
+// baseCurrency is where the reported cycles start when they go through it.
+const baseCurrency = "ARS"
+
+var hundred = currency.MoneyFromInt(100)
+
+// Opportunity is a cycle of trades returning to the starting currency.
+type Opportunity struct {
+	Trades []*market.Trade
+	// ProfitPercent is the net result of the cycle after fees
+	ProfitPercent currency.Money
+}
+
+// Route returns the cycle as "ARS → DAI (Buenbit) → ARS (Satoshi Tango)".
+func (o *Opportunity) Route() string {
+	return market.Route(o.Trades)
+}
+
+// key identifies the cycle regardless of its profit.
+func (o *Opportunity) key() string {
+	var parts []string
+	for _, t := range o.Trades {
+		parts = append(parts, t.Provider+":"+t.Market+":"+t.To)
+	}
+	return strings.Join(parts, "|")
+}
+
+// withdrawals holds the fraction of the funds kept when moving them out of each provider. The
+// trade fees are not included since the quotes already have the provider fees charged.
+type withdrawals map[string]currency.Money
+
+func newWithdrawals(providers []currency.ProviderInfo) withdrawals {
+	w := make(withdrawals)
+	for _, p := range providers {
+		w[p.Label] = currency.MoneyFromInt(1).Sub(currency.MoneyFromFloat(p.Fees.WithdrawalPercent).Div(hundred))
+	}
+	return w
+}
+
+// keep returns the fraction kept when withdrawing from the provider, everything for unknown ones.
+func (w withdrawals) keep(provider string) currency.Money {
+	if k, found := w[provider]; found {
+		return k
+	}
+	return currency.MoneyFromInt(1)
+}
+
+// findOpportunities returns every cycle of up to maxHops trades ordered by net profit. Each
+// cycle is reported once, starting at the base currency when it goes through it.
+func findOpportunities(trades []*market.Trade, maxHops int, w withdrawals) []*Opportunity {
+	byFrom := make(map[string][]*market.Trade)
+	for _, t := range trades {
+		byFrom[t.From] = append(byFrom[t.From], t)
+	}
+
+	var opportunities []*Opportunity
+	var path []*market.Trade
+	var walk func(start string, current string, result currency.Money)
+	walk = func(start string, current string, result currency.Money) {
+		for _, t := range byFrom[current] {
+			if !canVisit(path, t, start) {
+				continue
+			}
+
+			next := result.Mul(t.Rate)
+			if len(path) > 0 && path[len(path)-1].Provider != t.Provider {
+				next = next.Mul(w.keep(path[len(path)-1].Provider))
+			}
+
+			path = append(path, t)
+			if t.To == start {
+				if len(path) > 1 {
+					opportunities = append(opportunities, &Opportunity{
+						Trades:        append([]*market.Trade(nil), path...),
+						ProfitPercent: currency.PercentChange(currency.MoneyFromInt(1), next),
+					})
+				}
+			} else if len(path) < maxHops {
+				walk(start, t.To, next)
+			}
+			path = path[:len(path)-1]
+		}
+	}
+
+	for start := range byFrom {
+		walk(start, start, currency.MoneyFromInt(1))
+	}
+
+	sort.SliceStable(opportunities, func(i, j int) bool {
+		if c := opportunities[i].ProfitPercent.Cmp(opportunities[j].ProfitPercent); c != 0 {
+			return c > 0
+		}
+		return opportunities[i].key() < opportunities[j].key()
+	})
+	return opportunities
+}
+
+// canVisit keeps the cycles simple, not repeating a currency, and rotated so that the
+// start is the lowest ranked currency of the cycle.
+func canVisit(path []*market.Trade, t *market.Trade, start string) bool {
+	if t.To == start {
+		return true
+	}
+	if rank(t.To) < rank(start) {
+		return false
+	}
+	for _, p := range path {
+		if p.To == t.To {
+			return false
+		}
+	}
+	return true
+}
+
+func rank(c string) string {
+	if c == baseCurrency {
+		return ""
+	}
+	return c
+}
@@ -0,0 +1,116 @@
+package arbitrage
+
+import (
+	"context"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/store"
+
+	tb "github.com/go-telegram-bot-api/telegram-bot-api"
+	"go.uber.org/zap"
+)
+
+type notifier interface {
+	Send(c tb.Chattable) (tb.Message, error)
+}
+
+type templateEngine interface {
+	FormatArbitrageAlertMessage(o *Opportunity) (string, error)
+}
+
+type analyzer interface {
+	Analyze(ctx context.Context) *Analysis
+}
+
+// scheduler notifies the chats with an arbitrage alert when the best opportunity reaches
+// their threshold. A chat is notified again only once the opportunity changes.
+type scheduler struct {
+	config         *options.ArbitrageConfig
+	store          preferencesStore
+	analyzer       analyzer
+	notifier       notifier
+	templateEngine templateEngine
+	logger         *zap.Logger
+	// notified is the last opportunity sent to each chat
+	notified map[int64]string
+}
+
+func NewScheduler(c *options.ArbitrageConfig, s preferencesStore, a analyzer, n notifier, t templateEngine, l *zap.Logger) *scheduler {
+	return &scheduler{
+		config:         c,
+		store:          s,
+		analyzer:       a,
+		notifier:       n,
+		templateEngine: t,
+		logger:         l,
+		notified:       make(map[int64]string),
+	}
+}
+
+// Run checks the opportunities every check interval until the context is done.
+func (s *scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.check(ctx)
+		}
+	}
+}
+
+func (s *scheduler) check(ctx context.Context) {
+	preferences, err := s.store.ListChatPreferences()
+	if err != nil {
+		s.logger.Error("listing chat preferences", zap.Error(err))
+		return
+	}
+
+	var subscribed []*store.ChatPreferences
+	for _, p := range preferences {
+		if p.ArbitrageThreshold.IsPositive() {
+			subscribed = append(subscribed, p)
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+
+	var best *Opportunity
+	if a := s.analyzer.Analyze(ctx); len(a.Opportunities) > 0 {
+		best = a.Opportunities[0]
+	}
+
+	for _, p := range subscribed {
+		if best == nil || best.ProfitPercent.LessThan(p.ArbitrageThreshold) {
+			delete(s.notified, p.ChatID)
+			continue
+		}
+		if s.notified[p.ChatID] == best.key() {
+			continue
+		}
+		if s.notify(p.ChatID, best) {
+			s.notified[p.ChatID] = best.key()
+		}
+	}
+}
+
+func (s *scheduler) notify(chatID int64, o *Opportunity) bool {
+	text, err := s.templateEngine.FormatArbitrageAlertMessage(o)
+	if err != nil {
+		s.logger.Error("formatting arbitrage alert", zap.Error(err))
+		return false
+	}
+
+	msg := tb.NewMessage(chatID, text)
+	msg.ParseMode = tb.ModeHTML
+	if _, err := s.notifier.Send(msg); err != nil {
+		s.logger.Error("sending arbitrage alert", zap.Int64("chatID", chatID), zap.Error(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package arbitrage
+
+import (
+	"context"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+	"coinbani/pkg/market"
+	"coinbani/pkg/store"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidThreshold = errors.New("invalid threshold")
+
+type pricesFetcher interface {
+	Providers() []currency.ProviderInfo
+	GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices
+}
+
+type preferencesStore interface {
+	GetChatPreferences(chatID int64) (*store.ChatPreferences, error)
+	SaveChatPreferences(p *store.ChatPreferences) error
+	ListChatPreferences() ([]*store.ChatPreferences, error)
+}
+
+// Analysis holds the profitable cycles found in the current quotes, best first.
+type Analysis struct {
+	Opportunities []*Opportunity
+	// Unavailable are the providers left out because they failed or their data is stale
+	Unavailable []string
+}
+
+type service struct {
+	config          *options.ArbitrageConfig
+	currencyService pricesFetcher
+	store           preferencesStore
+}
+
+func NewService(c *options.ArbitrageConfig, cs pricesFetcher, s preferencesStore) *service {
+	return &service{config: c, currencyService: cs, store: s}
+}
+
+// Analyze fetches every provider and looks for cycles with a net profit.
+func (s *service) Analyze(ctx context.Context) *Analysis {
+	a := &Analysis{}
+
+	var priceLists []*currency.CurrencyPriceList
+	for _, result := range s.currencyService.GetAllLastPrices(ctx) {
+		if result.Err != nil || result.PriceList.IsStale() {
+			a.Unavailable = append(a.Unavailable, result.ProviderName)
+			continue
+		}
+		priceLists = append(priceLists, result.PriceList)
+	}
+
+	w := newWithdrawals(s.currencyService.Providers())
+	for _, o := range findOpportunities(market.BuildTrades(priceLists), s.config.MaxHops, w) {
+		if !o.ProfitPercent.IsPositive() || len(a.Opportunities) == s.config.MaxResults {
+			break
+		}
+		a.Opportunities = append(a.Opportunities, o)
+	}
+
+	return a
+}
+
+// SetAlertThreshold sets the minimum profit percent notified to the chat, 0 disables the alert.
+func (s *service) SetAlertThreshold(chatID int64, threshold currency.Money) error {
+	if threshold.IsNegative() {
+		return ErrInvalidThreshold
+	}
+
+	p, err := s.store.GetChatPreferences(chatID)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			return errors.Wrap(err, "getting chat preferences")
+		}
+		p = &store.ChatPreferences{ChatID: chatID}
+	}
+
+	p.ArbitrageThreshold = threshold
+	return errors.Wrap(s.store.SaveChatPreferences(p), "saving chat preferences")
+}
@@ -0,0 +1,109 @@
+package arbitrage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+type fakePricesFetcher struct {
+	results []*currency.ProviderPrices
+	fees    map[string]options.FeeModel
+}
+
+func (f *fakePricesFetcher) Providers() []currency.ProviderInfo {
+	var providers []currency.ProviderInfo
+	for _, r := range f.results {
+		providers = append(providers, currency.ProviderInfo{Label: r.ProviderName, Fees: f.fees[r.ProviderName]})
+	}
+	return providers
+}
+
+func (f *fakePricesFetcher) GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices {
+	return f.results
+}
+
+func TestService_Analyze(t *testing.T) {
+	prices := func(provider string, p ...*currency.CurrencyPrice) *currency.ProviderPrices {
+		return &currency.ProviderPrices{ProviderName: provider, PriceList: &currency.CurrencyPriceList{ProviderName: provider, Prices: p}}
+	}
+
+	tests := []struct {
+		name            string
+		results         []*currency.ProviderPrices
+		fees            map[string]options.FeeModel
+		config          *options.ArbitrageConfig
+		wantRoutes      []string
+		wantProfits     []string
+		wantUnavailable []string
+	}{
+		{
+			name: "buy on one exchange and sell on the other",
+			results: []*currency.ProviderPrices{
+				prices("Buenbit", &currency.CurrencyPrice{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("95"), AskPrice: currency.MustParseMoney("100")}),
+				prices("Satoshi Tango", &currency.CurrencyPrice{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("110"), AskPrice: currency.MustParseMoney("115")}),
+			},
+			config:      &options.ArbitrageConfig{MaxHops: 4, MaxResults: 5},
+			wantRoutes:  []string{"ARS → DAI (Buenbit) → ARS (Satoshi Tango)"},
+			wantProfits: []string{"10"},
+		},
+		{
+			name: "withdrawal fee charged when moving to another provider",
+			results: []*currency.ProviderPrices{
+				prices("Buenbit", &currency.CurrencyPrice{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("95"), AskPrice: currency.MustParseMoney("100")}),
+				prices("Satoshi Tango", &currency.CurrencyPrice{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("110"), AskPrice: currency.MustParseMoney("115")}),
+			},
+			fees:        map[string]options.FeeModel{"Buenbit": {TakerPercent: 1, WithdrawalPercent: 2}, "Satoshi Tango": {WithdrawalPercent: 5}},
+			config:      &options.ArbitrageConfig{MaxHops: 4, MaxResults: 5},
+			wantRoutes:  []string{"ARS → DAI (Buenbit) → ARS (Satoshi Tango)"},
+			wantProfits: []string{"7.8"},
+		},
+		{
+			name: "blue dollar against the crypto dollar",
+			results: []*currency.ProviderPrices{
+				prices("Dolar", &currency.CurrencyPrice{Desc: "Blue", BidPrice: currency.MustParseMoney("140"), AskPrice: currency.MustParseMoney("145")}),
+				prices("Buenbit",
+					&currency.CurrencyPrice{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("160"), AskPrice: currency.MustParseMoney("165")},
+					&currency.CurrencyPrice{Desc: "DAI/USD", BidPrice: currency.MustParseMoney("0.98"), AskPrice: currency.MustParseMoney("1")},
+				),
+			},
+			config:      &options.ArbitrageConfig{MaxHops: 3, MaxResults: 5},
+			wantRoutes:  []string{"ARS → USD (Dolar Blue) → DAI (Buenbit) → ARS (Buenbit)"},
+			wantProfits: []string{"10.344828"},
+		},
+		{
+			name: "no profitable cycle and failed providers",
+			results: []*currency.ProviderPrices{
+				prices("Buenbit", &currency.CurrencyPrice{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("95"), AskPrice: currency.MustParseMoney("100")}),
+				{ProviderName: "Satoshi Tango", Err: errors.New("timeout")},
+			},
+			config:          &options.ArbitrageConfig{MaxHops: 4, MaxResults: 5},
+			wantUnavailable: []string{"Satoshi Tango"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewService(tt.config, &fakePricesFetcher{results: tt.results, fees: tt.fees}, nil).Analyze(context.Background())
+
+			var routes []string
+			for i, o := range got.Opportunities {
+				routes = append(routes, o.Route())
+				if i < len(tt.wantProfits) && !o.ProfitPercent.Round(6).Equal(currency.MustParseMoney(tt.wantProfits[i])) {
+					t.Errorf("Analyze() profit of %s = %v, want %v", o.Route(), o.ProfitPercent, tt.wantProfits[i])
+				}
+			}
+			if !reflect.DeepEqual(routes, tt.wantRoutes) {
+				t.Errorf("Analyze() routes = %v, want %v", routes, tt.wantRoutes)
+			}
+			if !reflect.DeepEqual(got.Unavailable, tt.wantUnavailable) {
+				t.Errorf("Analyze() unavailable = %v, want %v", got.Unavailable, tt.wantUnavailable)
+			}
+		})
+	}
+}
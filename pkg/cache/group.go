@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+type call struct {
+	done    chan struct{}
+	val     interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// group coalesces concurrent calls sharing the same key into a single execution.
+type group struct {
+	lock  sync.Mutex
+	calls map[string]*call
+}
+
+func NewGroup() *group {
+	return &group{calls: make(map[string]*call)}
+}
+
+// Do executes fn for the given key, callers arriving while a call for the same
+// key is in flight wait for it and receive its results.
+// A caller stops waiting when its context is done, the context passed to fn is
+// only cancelled once every caller waiting for it is gone.
+func (g *group) Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	g.lock.Lock()
+	c, found := g.calls[key]
+	if !found {
+		c = g.start(key, fn)
+	}
+	c.waiters++
+	g.lock.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		g.lock.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			g.forget(key, c)
+		}
+		g.lock.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// start must be called holding the group lock.
+func (g *group) start(key string, fn func(ctx context.Context) (interface{}, error)) *call {
+	callCtx, cancel := context.WithCancel(context.Background())
+	c := &call{done: make(chan struct{}), cancel: cancel}
+	g.calls[key] = c
+
+	go func() {
+		c.val, c.err = fn(callCtx)
+		cancel()
+
+		g.lock.Lock()
+		g.forget(key, c)
+		g.lock.Unlock()
+		close(c.done)
+	}()
+
+	return c
+}
+
+// forget must be called holding the group lock.
+func (g *group) forget(key string, c *call) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// waitForWaiters blocks until n callers are waiting for the call of the key.
+func waitForWaiters(t *testing.T, g *group, key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.lock.Lock()
+		c, found := g.calls[key]
+		waiters := 0
+		if found {
+			waiters = c.waiters
+		}
+		g.lock.Unlock()
+
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d waiters", n)
+}
+
+func Test_group_Do_coalesces(t *testing.T) {
+	g := NewGroup()
+	release := make(chan struct{})
+	var calls int32
+	fn := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "prices", nil
+	}
+
+	const callers = 5
+	results := make(chan interface{}, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := g.Do(context.Background(), "bb", fn)
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+			}
+			results <- v
+		}()
+	}
+
+	waitForWaiters(t, g, "bb", callers)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Do() executed fn %d times, want 1", n)
+	}
+	for v := range results {
+		if v != "prices" {
+			t.Errorf("Do() = %v, want prices", v)
+		}
+	}
+}
+
+func Test_group_Do_waiterCancelled(t *testing.T) {
+	g := NewGroup()
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "prices", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := g.Do(ctx, "bb", fn)
+		cancelled <- err
+	}()
+	waitForWaiters(t, g, "bb", 1)
+
+	result := make(chan interface{}, 1)
+	go func() {
+		v, err := g.Do(context.Background(), "bb", fn)
+		if err != nil {
+			t.Errorf("Do() error = %v", err)
+		}
+		result <- v
+	}()
+	waitForWaiters(t, g, "bb", 2)
+
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() cancelled caller error = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if v := <-result; v != "prices" {
+		t.Errorf("Do() = %v, want prices", v)
+	}
+}
+
+func Test_group_Do_everyWaiterCancelled(t *testing.T) {
+	g := NewGroup()
+	fnCancelled := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		close(fnCancelled)
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := g.Do(ctx, "bb", fn); !errors.Is(err, context.Canceled) {
+				t.Errorf("Do() error = %v, want %v", err, context.Canceled)
+			}
+		}()
+	}
+	waitForWaiters(t, g, "bb", 2)
+
+	cancel()
+	wg.Wait()
+
+	select {
+	case <-fnCancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Do() didn't cancel the shared call")
+	}
+
+	// the next caller starts a new call
+	v, err := g.Do(context.Background(), "bb", func(ctx context.Context) (interface{}, error) {
+		return "prices", nil
+	})
+	if err != nil || v != "prices" {
+		t.Errorf("Do() = %v, %v, want prices", v, err)
+	}
+}
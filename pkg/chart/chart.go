@@ -0,0 +1,175 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWidth  = 800
+	defaultHeight = 400
+	margin        = 20
+	gridLines     = 4
+	lineWidth     = 2
+)
+
+var ErrNoData = errors.New("not enough data to render a chart")
+
+type NamedColor struct {
+	Name  string
+	Color color.RGBA
+}
+
+// Palette holds the colors assigned to the series in order, names are shown in captions.
+var Palette = []NamedColor{
+	{Name: "azul", Color: color.RGBA{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff}},
+	{Name: "naranja", Color: color.RGBA{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff}},
+	{Name: "verde", Color: color.RGBA{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff}},
+	{Name: "rojo", Color: color.RGBA{R: 0xd6, G: 0x27, B: 0x28, A: 0xff}},
+}
+
+var (
+	backgroundColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	gridColor       = color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}
+	axisColor       = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+)
+
+type Point struct {
+	At    time.Time
+	Value float64
+}
+
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// Bounds are the min and max values and times among the rendered series.
+type Bounds struct {
+	MinValue float64
+	MaxValue float64
+	From     time.Time
+	To       time.Time
+}
+
+type renderer struct {
+	width  int
+	height int
+}
+
+func NewRenderer() *renderer {
+	return &renderer{width: defaultWidth, height: defaultHeight}
+}
+
+// RenderPNG draws every series as a line, colored following the Palette order.
+func (r *renderer) RenderPNG(series []*Series) ([]byte, *Bounds, error) {
+	bounds, ok := computeBounds(series)
+	if !ok {
+		return nil, nil, ErrNoData
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, r.width, r.height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: backgroundColor}, image.Point{}, draw.Src)
+
+	plot := image.Rect(margin, margin, r.width-margin, r.height-margin)
+	drawGrid(img, plot)
+
+	for i, s := range series {
+		c := Palette[i%len(Palette)].Color
+		var prev image.Point
+		for j, p := range s.Points {
+			pt := toPixel(plot, bounds, p)
+			if j > 0 {
+				drawLine(img, prev, pt, c)
+			}
+			prev = pt
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return nil, nil, errors.Wrap(err, "encoding chart png")
+	}
+
+	return buf.Bytes(), bounds, nil
+}
+
+func computeBounds(series []*Series) (*Bounds, bool) {
+	b := &Bounds{MinValue: math.Inf(1), MaxValue: math.Inf(-1)}
+	points := 0
+	for _, s := range series {
+		for _, p := range s.Points {
+			points++
+			b.MinValue = math.Min(b.MinValue, p.Value)
+			b.MaxValue = math.Max(b.MaxValue, p.Value)
+			if b.From.IsZero() || p.At.Before(b.From) {
+				b.From = p.At
+			}
+			if p.At.After(b.To) {
+				b.To = p.At
+			}
+		}
+	}
+
+	return b, points >= 2
+}
+
+func toPixel(plot image.Rectangle, b *Bounds, p Point) image.Point {
+	x := 0.5
+	if span := b.To.Sub(b.From); span > 0 {
+		x = float64(p.At.Sub(b.From)) / float64(span)
+	}
+
+	y := 0.5
+	if span := b.MaxValue - b.MinValue; span > 0 {
+		y = (p.Value - b.MinValue) / span
+	}
+
+	return image.Point{
+		X: plot.Min.X + int(math.Round(x*float64(plot.Dx()-1))),
+		Y: plot.Max.Y - 1 - int(math.Round(y*float64(plot.Dy()-1))),
+	}
+}
+
+func drawGrid(img *image.RGBA, plot image.Rectangle) {
+	for i := 0; i <= gridLines; i++ {
+		y := plot.Min.Y + i*(plot.Dy()-1)/gridLines
+		c := gridColor
+		if i == gridLines {
+			c = axisColor
+		}
+		for x := plot.Min.X; x < plot.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	for y := plot.Min.Y; y < plot.Max.Y; y++ {
+		img.SetRGBA(plot.Min.X, y, axisColor)
+	}
+}
+
+// drawLine draws a thick line stepping along its longest axis.
+func drawLine(img *image.RGBA, from image.Point, to image.Point, c color.RGBA) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	steps := int(math.Max(math.Abs(float64(dx)), math.Abs(float64(dy))))
+	if steps == 0 {
+		steps = 1
+	}
+
+	for i := 0; i <= steps; i++ {
+		x := from.X + int(math.Round(float64(dx*i)/float64(steps)))
+		y := from.Y + int(math.Round(float64(dy*i)/float64(steps)))
+		for ox := 0; ox < lineWidth; ox++ {
+			for oy := 0; oy < lineWidth; oy++ {
+				img.SetRGBA(x+ox, y+oy, c)
+			}
+		}
+	}
+}
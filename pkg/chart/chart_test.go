@@ -0,0 +1,69 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRenderPNG(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		series  []*Series
+		wantErr error
+		wantMin float64
+		wantMax float64
+	}{
+		{
+			name:    "no series",
+			wantErr: ErrNoData,
+		},
+		{
+			name:    "single point",
+			series:  []*Series{{Name: "Dolar", Points: []Point{{At: now, Value: 140}}}},
+			wantErr: ErrNoData,
+		},
+		{
+			name: "two series",
+			series: []*Series{
+				{Name: "Dolar", Points: []Point{{At: now, Value: 140}, {At: now.Add(time.Hour), Value: 145}}},
+				{Name: "Buenbit", Points: []Point{{At: now.Add(time.Minute), Value: 150}, {At: now.Add(2 * time.Hour), Value: 138}}},
+			},
+			wantMin: 138,
+			wantMax: 150,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer()
+			image, bounds, err := r.RenderPNG(tt.series)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RenderPNG() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderPNG() unexpected error: %v", err)
+			}
+
+			if bounds.MinValue != tt.wantMin || bounds.MaxValue != tt.wantMax {
+				t.Errorf("RenderPNG() bounds = [%v, %v], want [%v, %v]", bounds.MinValue, bounds.MaxValue, tt.wantMin, tt.wantMax)
+			}
+
+			decoded, err := png.Decode(bytes.NewReader(image))
+			if err != nil {
+				t.Fatalf("decoding png: %v", err)
+			}
+			if got := decoded.Bounds().Dx(); got != defaultWidth {
+				t.Errorf("png width = %d, want %d", got, defaultWidth)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+
+	"github.com/pkg/errors"
+)
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerStatus is a snapshot of the circuit breaker of an upstream host.
+type BreakerStatus struct {
+	Host     string
+	State    BreakerState
+	Failures int
+	OpenedAt time.Time
+}
+
+// circuitBreaker opens after a number of consecutive failures, rejecting requests until
+// the open timeout elapses. Then a limited number of probe requests are let through
+// (half-open) and the breaker closes on the first success or opens again on failure.
+type circuitBreaker struct {
+	lock             sync.Mutex
+	config           *options.ClientConfig
+	state            BreakerState
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+}
+
+func newCircuitBreaker(c *options.ClientConfig) *circuitBreaker {
+	return &circuitBreaker{config: c, state: BreakerClosed}
+}
+
+// allow reports whether a request can be made, callers must report its outcome
+// calling done.
+func (b *circuitBreaker) allow() error {
+	if b.config.BreakerFailureThreshold <= 0 {
+		return nil
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.config.BreakerOpenTimeout {
+		b.state = BreakerHalfOpen
+		b.halfOpenInFlight = 0
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.halfOpenInFlight >= b.config.BreakerHalfOpenRequests {
+			return ErrCircuitOpen
+		}
+		b.halfOpenInFlight++
+	}
+
+	return nil
+}
+
+// done records the outcome of an allowed request, requests cancelled by the caller
+// are neither a success nor a failure.
+func (b *circuitBreaker) done(success bool, cancelled bool) {
+	if b.config.BreakerFailureThreshold <= 0 {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.state == BreakerHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+
+	switch {
+	case cancelled:
+		return
+	case success:
+		b.state = BreakerClosed
+		b.failures = 0
+	case b.state == BreakerHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.failures >= b.config.BreakerFailureThreshold {
+			b.open()
+		}
+	}
+}
+
+func (b *circuitBreaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+}
+
+func (b *circuitBreaker) status(host string) BreakerStatus {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	state := b.state
+	if state == BreakerOpen && time.Since(b.openedAt) >= b.config.BreakerOpenTimeout {
+		state = BreakerHalfOpen
+	}
+
+	return BreakerStatus{Host: host, State: state, Failures: b.failures, OpenedAt: b.openedAt}
+}
+
+type breakerRegistry struct {
+	lock     sync.Mutex
+	config   *options.ClientConfig
+	breakers map[string]*circuitBreaker
+}
+
+func newBreakerRegistry(c *options.ClientConfig) *breakerRegistry {
+	return &breakerRegistry{config: c, breakers: make(map[string]*circuitBreaker)}
+}
+
+func (r *breakerRegistry) get(host string) *circuitBreaker {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	b, found := r.breakers[host]
+	if !found {
+		b = newCircuitBreaker(r.config)
+		r.breakers[host] = b
+	}
+	return b
+}
+
+func (r *breakerRegistry) statuses() []BreakerStatus {
+	r.lock.Lock()
+	hosts := make([]string, 0, len(r.breakers))
+	for h := range r.breakers {
+		hosts = append(hosts, h)
+	}
+	r.lock.Unlock()
+
+	sort.Strings(hosts)
+	statuses := make([]BreakerStatus, 0, len(hosts))
+	for _, h := range hosts {
+		statuses = append(statuses, r.get(h).status(h))
+	}
+	return statuses
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func Test_restClient_Get_circuitBreaker(t *testing.T) {
+	cfg := &options.ClientConfig{
+		BreakerFailureThreshold: 2,
+		BreakerOpenTimeout:      50 * time.Millisecond,
+		BreakerHalfOpenRequests: 1,
+	}
+
+	tests := []struct {
+		name         string
+		statusCodes  []int
+		wait         time.Duration
+		wantErr      bool
+		wantOpenErr  bool
+		wantRequests int32
+		wantState    BreakerState
+	}{
+		{
+			name:         "opens after consecutive failures and fails fast",
+			statusCodes:  []int{http.StatusInternalServerError},
+			wantErr:      true,
+			wantOpenErr:  true,
+			wantRequests: 2,
+			wantState:    BreakerOpen,
+		},
+		{
+			name:         "rate limiting opens the circuit",
+			statusCodes:  []int{http.StatusTooManyRequests},
+			wantErr:      true,
+			wantOpenErr:  true,
+			wantRequests: 2,
+			wantState:    BreakerOpen,
+		},
+		{
+			name:         "client errors don't open the circuit",
+			statusCodes:  []int{http.StatusNotFound},
+			wantErr:      true,
+			wantRequests: 3,
+			wantState:    BreakerClosed,
+		},
+		{
+			name:         "half-open probe success closes the circuit",
+			statusCodes:  []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			wait:         60 * time.Millisecond,
+			wantRequests: 3,
+			wantState:    BreakerClosed,
+		},
+		{
+			name:         "half-open probe failure opens the circuit again",
+			statusCodes:  []int{http.StatusInternalServerError},
+			wait:         60 * time.Millisecond,
+			wantErr:      true,
+			wantRequests: 3,
+			wantState:    BreakerOpen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, requests := newTestServer(tt.statusCodes, nil)
+			defer s.Close()
+
+			c := NewRestClient(cfg, zap.NewNop())
+			req := &GetRequestBuilder{Url: s.URL, ParseResponse: parseBodyFunc}
+
+			c.Get(context.Background(), req)
+			c.Get(context.Background(), req)
+			time.Sleep(tt.wait)
+			_, err := c.Get(context.Background(), req)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (errors.Cause(err) == ErrCircuitOpen) != tt.wantOpenErr {
+				t.Errorf("Get() error = %v, want circuit open %v", err, tt.wantOpenErr)
+			}
+			if n := atomic.LoadInt32(requests); n != tt.wantRequests {
+				t.Errorf("Get() made %d requests, want %d", n, tt.wantRequests)
+			}
+
+			statuses := c.BreakerStatuses()
+			if len(statuses) != 1 {
+				t.Fatalf("BreakerStatuses() = %v, want one host", statuses)
+			}
+			if statuses[0].State != tt.wantState {
+				t.Errorf("BreakerStatuses() state = %v, want %v", statuses[0].State, tt.wantState)
+			}
+		})
+	}
+}
+
+func Test_restClient_Get_circuitOpenedWhileRetrying(t *testing.T) {
+	s, requests := newTestServer([]int{http.StatusInternalServerError}, nil)
+	defer s.Close()
+
+	c := NewRestClient(&options.ClientConfig{BreakerFailureThreshold: 2, BreakerOpenTimeout: time.Minute, BreakerHalfOpenRequests: 1}, zap.NewNop())
+	_, err := c.Get(context.Background(), &GetRequestBuilder{
+		Url:           s.URL,
+		ParseResponse: parseBodyFunc,
+		RetryPolicy:   &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Get() error = %v, want status %d", err, http.StatusInternalServerError)
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Get() error = %v, want the upstream error", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("Get() made %d requests, want 2", n)
+	}
+}
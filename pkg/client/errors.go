@@ -0,0 +1,51 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrStatus   = errors.New("unexpected response status")
+	ErrTooLarge = errors.New("response body too large")
+	ErrDecode   = errors.New("decoding response")
+)
+
+// StatusError is returned for responses with a non 200 status code, it matches ErrStatus.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("response status code is %d", e.StatusCode)
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrStatus
+}
+
+// kindError annotates an error with one of the client error kinds so callers can match it with errors.Is.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+func withKind(kind error, err error) error {
+	if errors.Is(err, kind) {
+		return err
+	}
+	return &kindError{kind: kind, err: err}
+}
@@ -1,56 +1,284 @@
 package client
 
 import (
-	"fmt"
+	"bytes"
+	"context"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"time"
 
+	"coinbani/cmd/coinbani/options"
+
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 )
 
+const maxDrainSize = 64 << 10
+
 type Http interface {
-	Get(req *GetRequestBuilder) (interface{}, error)
+	Get(ctx context.Context, req *GetRequestBuilder) (interface{}, error)
+	Post(ctx context.Context, req *PostRequestBuilder) (interface{}, error)
 }
 
 type restClient struct {
-	client *http.Client
+	client   *http.Client
+	config   *options.ClientConfig
+	breakers *breakerRegistry
+	logger   *zap.Logger
 }
 
-func NewRestClient() *restClient {
+func NewRestClient(cfg *options.ClientConfig, l *zap.Logger) *restClient {
 	c := &http.Client{
 		Transport: &http.Transport{
 			TLSHandshakeTimeout: 5 * time.Second,
 			MaxIdleConns:        5,
 			MaxConnsPerHost:     10,
 		},
-		Timeout: 10 * time.Second,
 	}
 	return &restClient{
-		client: c,
+		client:   c,
+		config:   cfg,
+		breakers: newBreakerRegistry(cfg),
+		logger:   l,
 	}
 }
 
+// BreakerStatuses returns the circuit breaker state of every upstream host requested so far.
+func (c *restClient) BreakerStatuses() []BreakerStatus {
+	return c.breakers.statuses()
+}
+
 type GetRequestBuilder struct {
-	Url           string
+	Url string
+	// ParseResponse decodes the response body, the client closes it afterwards
 	ParseResponse func(response *http.Response) (interface{}, error)
+	// ContentType is the expected response media type, any type is accepted when empty
+	ContentType string
+	// RetryPolicy is optional, requests are not retried by default
+	RetryPolicy *RetryPolicy
 }
 
-func (c *restClient) Get(req *GetRequestBuilder) (interface{}, error) {
-	// fetch from service
-	r, err := http.NewRequest(http.MethodGet, req.Url, nil)
+// PostRequestBuilder is like GetRequestBuilder for requests sending a body, e.g. search endpoints.
+type PostRequestBuilder struct {
+	Url  string
+	Body []byte
+	// BodyContentType is the media type of the sent body
+	BodyContentType string
+	// ParseResponse decodes the response body, the client closes it afterwards
+	ParseResponse func(response *http.Response) (interface{}, error)
+	// ContentType is the expected response media type, any type is accepted when empty
+	ContentType string
+	// RetryPolicy is optional, requests are not retried by default. Only set it for idempotent requests.
+	RetryPolicy *RetryPolicy
+}
+
+// request is a GET or POST request as sent by the retry loop.
+type request struct {
+	method          string
+	url             string
+	body            []byte
+	bodyContentType string
+	parseResponse   func(response *http.Response) (interface{}, error)
+	contentType     string
+	retryPolicy     *RetryPolicy
+}
+
+// Get fetches and parses the given URL, the request is bounded by the context deadline.
+// Failed attempts are retried following the request retry policy, requests to hosts
+// with an open circuit breaker fail fast with ErrCircuitOpen.
+// Errors match ErrStatus, ErrTooLarge or ErrDecode depending on what failed.
+func (c *restClient) Get(ctx context.Context, req *GetRequestBuilder) (interface{}, error) {
+	return c.send(ctx, &request{
+		method:        http.MethodGet,
+		url:           req.Url,
+		parseResponse: req.ParseResponse,
+		contentType:   req.ContentType,
+		retryPolicy:   req.RetryPolicy,
+	})
+}
+
+// Post sends the body to the given URL and parses the response, it behaves like Get
+// regarding deadlines, retries, circuit breakers and errors.
+func (c *restClient) Post(ctx context.Context, req *PostRequestBuilder) (interface{}, error) {
+	return c.send(ctx, &request{
+		method:          http.MethodPost,
+		url:             req.Url,
+		body:            req.Body,
+		bodyContentType: req.BodyContentType,
+		parseResponse:   req.ParseResponse,
+		contentType:     req.ContentType,
+		retryPolicy:     req.RetryPolicy,
+	})
+}
+
+func (c *restClient) send(ctx context.Context, req *request) (interface{}, error) {
+	policy := req.retryPolicy
+	if policy == nil {
+		policy = NoRetry
+	}
+
+	u, err := url.Parse(req.url)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing request URL")
+	}
+	breaker := c.breakers.get(u.Host)
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if err := breaker.allow(); err != nil {
+			// the upstream error is what opened the circuit
+			if lastErr != nil {
+				return nil, errors.Wrapf(lastErr, "retry stopped by open circuit of %s", u.Host)
+			}
+			return nil, errors.Wrapf(err, "requesting %s", u.Host)
+		}
+
+		res, err := c.do(ctx, req)
+		// rate limited requests count as failures so the breaker backs off the host
+		breaker.done(err == nil && res.StatusCode < http.StatusInternalServerError && res.StatusCode != http.StatusTooManyRequests, ctx.Err() != nil)
+		if err == nil && res.StatusCode == http.StatusOK {
+			return c.parse(req, res)
+		}
+
+		var delay time.Duration
+		var requested bool
+		retryable := ctx.Err() == nil
+		if err != nil {
+			err = errors.Wrap(err, "fetching response from service")
+		} else {
+			err = &StatusError{StatusCode: res.StatusCode}
+			retryable = retryable && policy.isRetryableStatus(res.StatusCode)
+			delay, requested = retryAfter(res)
+			drainAndClose(res.Body)
+		}
+
+		if !retryable || attempt >= policy.MaxAttempts {
+			return nil, err
+		}
+		// waiting longer than the server asks would fail anyway
+		if requested && !policy.canWait(ctx, delay) {
+			return nil, err
+		}
+
+		lastErr = err
+
+		if backoff := policy.backoff(attempt); backoff > delay {
+			delay = backoff
+		}
+
+		c.logger.Warn("retrying request",
+			zap.String("url", req.url),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Wrap(err, "retry cancelled")
+		}
+	}
+}
+
+func (c *restClient) do(ctx context.Context, req *request) (*http.Response, error) {
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+
+	r, err := http.NewRequestWithContext(ctx, req.method, req.url, body)
 	if err != nil {
-		return nil, errors.Wrap(err, "creating HTTP Get request")
+		return nil, errors.Wrapf(err, "creating HTTP %s request", req.method)
+	}
+	if req.bodyContentType != "" {
+		r.Header.Set("Content-Type", req.bodyContentType)
 	}
 	r.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/83.0.4103.61 Safari/537.36")
 
 	res, err := c.client.Do(r)
-	if err != nil || res == nil {
-		return nil, errors.Wrap(err, "fetching response from service")
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, errors.New("empty response")
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, errors.New(fmt.Sprintf("response status code is %d", res.StatusCode))
+	return res, nil
+}
+
+// parse validates the response and decodes it, the body is always drained and closed.
+func (c *restClient) parse(req *request, res *http.Response) (interface{}, error) {
+	defer func() {
+		drainAndClose(res.Body)
+	}()
+
+	if err := checkContentType(res, req.contentType); err != nil {
+		return nil, withKind(ErrDecode, err)
 	}
 
-	return req.ParseResponse(res)
+	if max := c.config.MaxResponseSize; max > 0 {
+		if res.ContentLength > max {
+			return nil, errors.Wrapf(ErrTooLarge, "content length %d exceeds %d bytes", res.ContentLength, max)
+		}
+		res.Body = &limitedBody{ReadCloser: res.Body, remaining: max}
+	}
+
+	v, err := req.parseResponse(res)
+	if err != nil {
+		if errors.Is(err, ErrTooLarge) {
+			return nil, errors.Wrapf(ErrTooLarge, "body exceeds %d bytes", c.config.MaxResponseSize)
+		}
+		return nil, withKind(ErrDecode, err)
+	}
+
+	return v, nil
+}
+
+func checkContentType(res *http.Response, expected string) error {
+	header := res.Header.Get("Content-Type")
+	if expected == "" || header == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return errors.Wrap(err, "parsing content type")
+	}
+	if mediaType != expected {
+		return errors.Errorf("unexpected content type %s, want %s", mediaType, expected)
+	}
+	return nil
+}
+
+// limitedBody fails with ErrTooLarge when more than the remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// drainAndClose reads what's left of the body, up to maxDrainSize, so the connection can be reused.
+func drainAndClose(body io.ReadCloser) {
+	io.CopyN(io.Discard, body, maxDrainSize)
+	body.Close()
 }
@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var parseBodyFunc = func(r *http.Response) (interface{}, error) {
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	return string(b), err
+}
+
+// newTestServer replies with the given status codes in order, the last one is repeated.
+func newTestServer(statusCodes []int, header http.Header) (*httptest.Server, *int32) {
+	var requests int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&requests, 1)) - 1
+		if i >= len(statusCodes) {
+			i = len(statusCodes) - 1
+		}
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(statusCodes[i])
+		w.Write([]byte("ok"))
+	}))
+	return s, &requests
+}
+
+func Test_restClient_Get_retries(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	tests := []struct {
+		name         string
+		statusCodes  []int
+		header       http.Header
+		policy       *RetryPolicy
+		wantErr      bool
+		wantRequests int32
+		minElapsed   time.Duration
+	}{
+		{
+			name:         "succeeds without retrying",
+			statusCodes:  []int{http.StatusOK},
+			policy:       policy,
+			wantRequests: 1,
+		},
+		{
+			name:         "retries retryable status until success",
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			policy:       policy,
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			statusCodes:  []int{http.StatusInternalServerError},
+			policy:       policy,
+			wantErr:      true,
+			wantRequests: 3,
+		},
+		{
+			name:         "doesn't retry non retryable status",
+			statusCodes:  []int{http.StatusNotFound, http.StatusOK},
+			policy:       policy,
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "retries only configured status codes",
+			statusCodes:  []int{http.StatusNotFound, http.StatusOK},
+			policy:       &RetryPolicy{MaxAttempts: 2, RetryableStatus: []int{http.StatusNotFound}},
+			wantRequests: 2,
+		},
+		{
+			name:         "doesn't retry without policy",
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "honors Retry-After header",
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusOK},
+			header:       http.Header{"Retry-After": []string{"1"}},
+			policy:       &RetryPolicy{MaxAttempts: 2, MaxBackoff: 2 * time.Second},
+			wantRequests: 2,
+			minElapsed:   time.Second,
+		},
+		{
+			name:         "gives up when Retry-After exceeds max backoff",
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusOK},
+			header:       http.Header{"Retry-After": []string{"1"}},
+			policy:       policy,
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, requests := newTestServer(tt.statusCodes, tt.header)
+			defer s.Close()
+
+			c := NewRestClient(&options.ClientConfig{}, zap.NewNop())
+			start := time.Now()
+			got, err := c.Get(context.Background(), &GetRequestBuilder{
+				Url:           s.URL,
+				ParseResponse: parseBodyFunc,
+				RetryPolicy:   tt.policy,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != "ok" {
+				t.Errorf("Get() = %v, want ok", got)
+			}
+			if n := atomic.LoadInt32(requests); n != tt.wantRequests {
+				t.Errorf("Get() made %d requests, want %d", n, tt.wantRequests)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("Get() took %v, want at least %v", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func Test_restClient_Get_retryAfterPastDeadline(t *testing.T) {
+	s, requests := newTestServer([]int{http.StatusTooManyRequests, http.StatusOK}, http.Header{"Retry-After": []string{"1"}})
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	c := NewRestClient(&options.ClientConfig{}, zap.NewNop())
+	_, err := c.Get(ctx, &GetRequestBuilder{
+		Url:           s.URL,
+		ParseResponse: parseBodyFunc,
+		RetryPolicy:   &RetryPolicy{MaxAttempts: 2, MaxBackoff: 2 * time.Second},
+	})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Get() error = %v, want status %d", err, http.StatusTooManyRequests)
+	}
+	if ctx.Err() != nil {
+		t.Error("Get() waited until the context deadline")
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("Get() made %d requests, want 1", n)
+	}
+}
+
+func Test_restClient_Get_cancelledWhileWaiting(t *testing.T) {
+	s, requests := newTestServer([]int{http.StatusServiceUnavailable}, nil)
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := NewRestClient(&options.ClientConfig{}, zap.NewNop())
+	_, err := c.Get(ctx, &GetRequestBuilder{
+		Url:           s.URL,
+		ParseResponse: parseBodyFunc,
+		RetryPolicy:   &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second},
+	})
+	if err == nil {
+		t.Fatal("Get() expected error after context deadline")
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("Get() made %d requests, want 1", n)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{retry: 1, want: 100 * time.Millisecond},
+		{retry: 2, want: 200 * time.Millisecond},
+		{retry: 3, want: 400 * time.Millisecond},
+		{retry: 5, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.retry); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicy_backoff_jitterCapped(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Second, Jitter: 1}
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got > p.MaxBackoff {
+			t.Fatalf("backoff(1) = %v, want at most %v", got, p.MaxBackoff)
+		}
+	}
+}
+
+func Test_restClient_Get_errors(t *testing.T) {
+	jsonParser := func(r *http.Response) (interface{}, error) {
+		var v map[string]string
+		err := json.NewDecoder(r.Body).Decode(&v)
+		return v, err
+	}
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		contentType string
+		body        string
+		wantErr     error
+	}{
+		{
+			name:        "valid response",
+			statusCode:  http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body:        `{"price": "10"}`,
+		},
+		{
+			name:       "non 200 status",
+			statusCode: http.StatusNotFound,
+			body:       `{}`,
+			wantErr:    ErrStatus,
+		},
+		{
+			name:        "body over the size limit",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"price": "` + strings.Repeat("1", 64) + `"}`,
+			wantErr:     ErrTooLarge,
+		},
+		{
+			name:        "invalid json",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"price":`,
+			wantErr:     ErrDecode,
+		},
+		{
+			name:        "unexpected content type",
+			statusCode:  http.StatusOK,
+			contentType: "text/html",
+			body:        `<html></html>`,
+			wantErr:     ErrDecode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.body))
+			}))
+			defer s.Close()
+
+			c := NewRestClient(&options.ClientConfig{MaxResponseSize: 32}, zap.NewNop())
+			_, err := c.Get(context.Background(), &GetRequestBuilder{
+				Url:           s.URL,
+				ParseResponse: jsonParser,
+				ContentType:   "application/json",
+			})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Get() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Get() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
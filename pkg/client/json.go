@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const jsonContentType = "application/json"
+
+// JSONRequest describes a request whose JSON response is decoded into a T.
+type JSONRequest[T any] struct {
+	Url         string
+	RetryPolicy *RetryPolicy
+	// Validate is optional, it's called with the decoded response and its error is returned as ErrDecode
+	Validate func(v *T) error
+}
+
+// GetJSON fetches the request URL through c and decodes the response body into a new T.
+func GetJSON[T any](ctx context.Context, c Http, req *JSONRequest[T]) (*T, error) {
+	res, err := c.Get(ctx, &GetRequestBuilder{
+		Url:         req.Url,
+		ContentType: jsonContentType,
+		RetryPolicy: req.RetryPolicy,
+		ParseResponse: func(r *http.Response) (interface{}, error) {
+			return decodeJSON(r, req.Validate)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asType[T](res)
+}
+
+// PostJSON sends body encoded as JSON to the request URL through c and decodes the response body into a new T.
+func PostJSON[T any](ctx context.Context, c Http, req *JSONRequest[T], body interface{}) (*T, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding request json")
+	}
+
+	res, err := c.Post(ctx, &PostRequestBuilder{
+		Url:             req.Url,
+		Body:            data,
+		BodyContentType: jsonContentType,
+		ContentType:     jsonContentType,
+		RetryPolicy:     req.RetryPolicy,
+		ParseResponse: func(r *http.Response) (interface{}, error) {
+			return decodeJSON(r, req.Validate)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asType[T](res)
+}
+
+// asType checks the parsed response is a T, e.g. when c is a fake not using the request parser.
+func asType[T any](res interface{}) (*T, error) {
+	v, ok := res.(*T)
+	if !ok {
+		return nil, errors.Wrapf(ErrDecode, "unexpected response type %T", res)
+	}
+	return v, nil
+}
+
+func decodeJSON[T any](r *http.Response, validate func(v *T) error) (*T, error) {
+	v := new(T)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return nil, errors.Wrap(err, "decoding response json")
+	}
+
+	if validate != nil {
+		if err := validate(v); err != nil {
+			return nil, errors.Wrap(err, "validating response")
+		}
+	}
+
+	return v, nil
+}
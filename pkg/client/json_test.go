@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"coinbani/cmd/coinbani/options"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type testPrice struct {
+	Bid float64 `json:"bid"`
+	Ask float64 `json:"ask"`
+}
+
+func TestGetJSON(t *testing.T) {
+	validate := func(p *testPrice) error {
+		if p.Bid <= 0 || p.Ask <= 0 {
+			return errors.New("missing prices")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		want    *testPrice
+		wantErr error
+	}{
+		{
+			name: "decodes into the requested type",
+			body: `{"bid": 10.5, "ask": 11}`,
+			want: &testPrice{Bid: 10.5, Ask: 11},
+		},
+		{
+			name:    "validation error",
+			body:    `{"bid": 10.5}`,
+			wantErr: ErrDecode,
+		},
+		{
+			name:    "type mismatch",
+			body:    `{"bid": "10.5", "ask": 11}`,
+			wantErr: ErrDecode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer s.Close()
+
+			c := NewRestClient(&options.ClientConfig{}, zap.NewNop())
+			got, err := GetJSON(context.Background(), c, &JSONRequest[testPrice]{Url: s.URL, Validate: validate})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetJSON() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetJSON() unexpected error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("GetJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// unparsedClient returns a response that wasn't decoded by the request parser.
+type unparsedClient struct{}
+
+func (unparsedClient) Get(ctx context.Context, req *GetRequestBuilder) (interface{}, error) {
+	return "ok", nil
+}
+
+func (unparsedClient) Post(ctx context.Context, req *PostRequestBuilder) (interface{}, error) {
+	return "ok", nil
+}
+
+func TestGetJSON_unexpectedType(t *testing.T) {
+	if _, err := GetJSON(context.Background(), unparsedClient{}, &JSONRequest[testPrice]{}); !errors.Is(err, ErrDecode) {
+		t.Errorf("GetJSON() error = %v, want %v", err, ErrDecode)
+	}
+	if _, err := PostJSON(context.Background(), unparsedClient{}, &JSONRequest[testPrice]{}, nil); !errors.Is(err, ErrDecode) {
+		t.Errorf("PostJSON() error = %v, want %v", err, ErrDecode)
+	}
+}
+
+func TestPostJSON(t *testing.T) {
+	type testQuery struct {
+		Asset string `json:"asset"`
+	}
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *testPrice
+		wantErr error
+	}{
+		{
+			name:   "sends the body and decodes the response",
+			status: http.StatusOK,
+			body:   `{"bid": 10.5, "ask": 11}`,
+			want:   &testPrice{Bid: 10.5, Ask: 11},
+		},
+		{
+			name:    "error status",
+			status:  http.StatusBadRequest,
+			wantErr: ErrStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var q testQuery
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != jsonContentType || json.NewDecoder(r.Body).Decode(&q) != nil || q.Asset != "USDT" {
+					t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer s.Close()
+
+			c := NewRestClient(&options.ClientConfig{}, zap.NewNop())
+			got, err := PostJSON(context.Background(), c, &JSONRequest[testPrice]{Url: s.URL}, &testQuery{Asset: "USDT"})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("PostJSON() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PostJSON() unexpected error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("PostJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryableStatus are the status codes retried when a policy doesn't define its own.
+var DefaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy defines how failed requests are retried.
+// Transport errors are always retried, responses only when their status code is retryable.
+type RetryPolicy struct {
+	// MaxAttempts includes the first request, values lower than 2 disable retries
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter randomizes each backoff by up to the given fraction, between 0 and 1
+	Jitter          float64
+	RetryableStatus []int
+}
+
+// NoRetry performs a single attempt.
+var NoRetry = &RetryPolicy{MaxAttempts: 1}
+
+func (p *RetryPolicy) isRetryableStatus(statusCode int) bool {
+	codes := p.RetryableStatus
+	if len(codes) == 0 {
+		codes = DefaultRetryableStatus
+	}
+
+	for _, c := range codes {
+		if c == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry, starting at 1, never above MaxBackoff.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay = delay * (1 - jitter + 2*jitter*rand.Float64())
+	}
+
+	// capped after the jitter so the delay never exceeds the max backoff
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	return time.Duration(delay)
+}
+
+// canWait reports whether a delay requested by the server fits the max backoff and the
+// time left before the context deadline.
+func (p *RetryPolicy) canWait(ctx context.Context, delay time.Duration) bool {
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+	return true
+}
+
+// retryAfter parses the Retry-After header, either in seconds or as an HTTP date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	v := res.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(v); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,36 @@
+package compare
+
+import (
+	"strings"
+)
+
+// quoteCurrencies are tried as suffix when the pair has no separator, e.g. "DAIARS".
+var quoteCurrencies = []string{"USDT", "USDC", "ARS", "USD", "BTC"}
+
+// pairAliases maps provider descriptions that don't follow the BASE/QUOTE convention,
+// Buenbit lists the ARS price of a dollar as "ARS/USD".
+var pairAliases = map[string]string{
+	"ARS/USD": "USD/ARS",
+}
+
+// NormalizePair returns the pair as BASE/QUOTE in upper case so the same pair matches across
+// providers, e.g. "dai-ars", "DAI ARS" and "daiars" all become "DAI/ARS". Names without a quote
+// currency such as "Blue" are only upper cased.
+func NormalizePair(pair string) string {
+	p := strings.ToUpper(strings.TrimSpace(pair))
+	p = strings.NewReplacer("-", "/", "_", "/", " ", "/").Replace(p)
+
+	if !strings.Contains(p, "/") {
+		for _, quote := range quoteCurrencies {
+			if len(p) > len(quote) && strings.HasSuffix(p, quote) {
+				p = strings.TrimSuffix(p, quote) + "/" + quote
+				break
+			}
+		}
+	}
+
+	if alias, found := pairAliases[p]; found {
+		return alias
+	}
+	return p
+}
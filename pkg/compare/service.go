@@ -0,0 +1,133 @@
+package compare
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+type Side string
+
+const (
+	// SideBuy compares the ask prices, the lowest is the best
+	SideBuy Side = "comprar"
+	// SideSell compares the bid prices, the highest is the best
+	SideSell Side = "vender"
+)
+
+var (
+	ErrInvalidSide = errors.New("invalid side")
+	ErrNoQuotes    = errors.New("no provider quotes the pair")
+)
+
+// ParseSide parses the side as typed by the user.
+func ParseSide(s string) (Side, error) {
+	switch Side(strings.ToLower(s)) {
+	case SideBuy:
+		return SideBuy, nil
+	case SideSell:
+		return SideSell, nil
+	default:
+		return "", errors.Wrapf(ErrInvalidSide, "unknown side %s", s)
+	}
+}
+
+// Quote is the price of the pair in a provider for the compared side.
+type Quote struct {
+	Provider string
+	Pair     string
+	Price    currency.Money
+	Stale    bool
+}
+
+// Comparison holds the quotes of a pair ordered from the best to the worst price.
+type Comparison struct {
+	Pair   string
+	Side   Side
+	Quotes []*Quote
+	// Unavailable are the providers that couldn't be fetched
+	Unavailable []string
+}
+
+func (c *Comparison) Best() *Quote {
+	return c.Quotes[0]
+}
+
+// RunnerUp returns the second best quote, nil when only one provider quotes the pair.
+func (c *Comparison) RunnerUp() *Quote {
+	if len(c.Quotes) < 2 {
+		return nil
+	}
+	return c.Quotes[1]
+}
+
+// Spread returns how much better the best quote is than the runner-up, in price and percent.
+func (c *Comparison) Spread() (currency.Money, currency.Money) {
+	runnerUp := c.RunnerUp()
+	if runnerUp == nil {
+		return currency.Money{}, currency.Money{}
+	}
+
+	diff := runnerUp.Price.Sub(c.Best().Price)
+	if c.Side == SideSell {
+		diff = diff.Neg()
+	}
+	return diff, diff.Div(runnerUp.Price).Mul(currency.MoneyFromInt(100))
+}
+
+type pricesFetcher interface {
+	GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices
+}
+
+type service struct {
+	currencyService pricesFetcher
+}
+
+func NewService(cs pricesFetcher) *service {
+	return &service{currencyService: cs}
+}
+
+// Compare fetches every provider and ranks their quotes of the pair for the given side.
+func (s *service) Compare(ctx context.Context, pair string, side Side) (*Comparison, error) {
+	normalized := NormalizePair(pair)
+	c := &Comparison{Pair: normalized, Side: side}
+
+	for _, result := range s.currencyService.GetAllLastPrices(ctx) {
+		if result.Err != nil {
+			c.Unavailable = append(c.Unavailable, result.ProviderName)
+			continue
+		}
+
+		for _, price := range result.PriceList.Prices {
+			if NormalizePair(price.Desc) != normalized {
+				continue
+			}
+
+			q := &Quote{Provider: result.ProviderName, Pair: price.Desc, Price: price.AskPrice, Stale: result.PriceList.IsStale()}
+			if side == SideSell {
+				q.Price = price.BidPrice
+			}
+			if q.Price.IsPositive() {
+				c.Quotes = append(c.Quotes, q)
+			}
+			break
+		}
+	}
+
+	if len(c.Quotes) == 0 {
+		return nil, ErrNoQuotes
+	}
+
+	sort.SliceStable(c.Quotes, func(i, j int) bool {
+		if side == SideSell {
+			return c.Quotes[i].Price.GreaterThan(c.Quotes[j].Price)
+		}
+		return c.Quotes[i].Price.LessThan(c.Quotes[j].Price)
+	})
+
+	return c, nil
+}
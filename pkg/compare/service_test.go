@@ -0,0 +1,124 @@
+package compare
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+type fakePricesFetcher []*currency.ProviderPrices
+
+func (f fakePricesFetcher) GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices {
+	return f
+}
+
+func TestNormalizePair(t *testing.T) {
+	tests := []struct {
+		pair string
+		want string
+	}{
+		{pair: "DAI/ARS", want: "DAI/ARS"},
+		{pair: "dai-ars", want: "DAI/ARS"},
+		{pair: "btc usd", want: "BTC/USD"},
+		{pair: "daiars", want: "DAI/ARS"},
+		{pair: "ARS/USD", want: "USD/ARS"},
+		{pair: "Blue", want: "BLUE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pair, func(t *testing.T) {
+			if got := NormalizePair(tt.pair); got != tt.want {
+				t.Errorf("NormalizePair() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Compare(t *testing.T) {
+	fetcher := fakePricesFetcher{
+		{ProviderName: "Buenbit", PriceList: &currency.CurrencyPriceList{Prices: []*currency.CurrencyPrice{
+			{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("125"), AskPrice: currency.MustParseMoney("160")},
+			{Desc: "BTC/ARS", BidPrice: currency.MustParseMoney("5000000"), AskPrice: currency.MustParseMoney("5100000")},
+		}}},
+		{ProviderName: "Satoshi Tango", PriceList: &currency.CurrencyPriceList{Prices: []*currency.CurrencyPrice{
+			{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("150"), AskPrice: currency.MustParseMoney("200")},
+		}}},
+		{ProviderName: "Dolar", PriceList: &currency.CurrencyPriceList{Prices: []*currency.CurrencyPrice{
+			{Desc: "Blue", BidPrice: currency.MustParseMoney("140"), AskPrice: currency.MustParseMoney("145")},
+		}}},
+		{ProviderName: "Ripio", Err: errors.New("timeout")},
+	}
+
+	tests := []struct {
+		name        string
+		pair        string
+		side        Side
+		wantQuotes  []*Quote
+		wantSpread  currency.Money
+		wantPercent currency.Money
+		wantErr     error
+	}{
+		{
+			name: "buy takes the lowest ask",
+			pair: "dai-ars",
+			side: SideBuy,
+			wantQuotes: []*Quote{
+				{Provider: "Buenbit", Pair: "DAI/ARS", Price: currency.MustParseMoney("160")},
+				{Provider: "Satoshi Tango", Pair: "DAI/ARS", Price: currency.MustParseMoney("200")},
+			},
+			wantSpread:  currency.MustParseMoney("40"),
+			wantPercent: currency.MustParseMoney("20"),
+		},
+		{
+			name: "sell takes the highest bid",
+			pair: "DAI/ARS",
+			side: SideSell,
+			wantQuotes: []*Quote{
+				{Provider: "Satoshi Tango", Pair: "DAI/ARS", Price: currency.MustParseMoney("150")},
+				{Provider: "Buenbit", Pair: "DAI/ARS", Price: currency.MustParseMoney("125")},
+			},
+			wantSpread:  currency.MustParseMoney("25"),
+			wantPercent: currency.MustParseMoney("20"),
+		},
+		{
+			name:       "single provider",
+			pair:       "BTC/ARS",
+			side:       SideBuy,
+			wantQuotes: []*Quote{{Provider: "Buenbit", Pair: "BTC/ARS", Price: currency.MustParseMoney("5100000")}},
+		},
+		{
+			name:    "unknown pair",
+			pair:    "XRP/ARS",
+			side:    SideBuy,
+			wantErr: ErrNoQuotes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(fetcher).Compare(context.Background(), tt.pair, tt.side)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Compare() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.Quotes, tt.wantQuotes) {
+				t.Errorf("Compare() quotes = %+v, want %+v", got.Quotes, tt.wantQuotes)
+			}
+			if !reflect.DeepEqual(got.Unavailable, []string{"Ripio"}) {
+				t.Errorf("Compare() unavailable = %v", got.Unavailable)
+			}
+
+			spread, percent := got.Spread()
+			if !spread.Equal(tt.wantSpread) || !percent.Equal(tt.wantPercent) {
+				t.Errorf("Spread() = %v, %v, want %v, %v", spread, percent, tt.wantSpread, tt.wantPercent)
+			}
+		})
+	}
+}
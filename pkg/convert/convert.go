@@ -0,0 +1,124 @@
+package convert
+
+import (
+	"strings"
+
+	"coinbani/pkg/currency"
+	"coinbani/pkg/market"
+
+	"github.com/pkg/errors"
+)
+
+// usd is the currency the dollar markets convert to
+const usd = "USD"
+
+var ErrInvalidFormat = errors.New("invalid conversion format")
+
+// currencyAliases are the names users type for a currency code.
+var currencyAliases = map[string]string{
+	"PESO":    "ARS",
+	"PESOS":   "ARS",
+	"DOLAR":   "USD",
+	"DOLARES": "USD",
+	"DÓLAR":   "USD",
+	"DÓLARES": "USD",
+}
+
+// Request is a conversion as typed by the user. Typing a dollar market such as Blue instead of
+// USD converts through that market only.
+type Request struct {
+	Amount   currency.Money
+	From     string
+	To       string
+	Provider string
+	// USDMarket restricts the dollar quotes to a market, empty for any
+	USDMarket string
+}
+
+// Parse parses the arguments of the convert command: <monto> <desde> <hacia> [proveedor],
+// the provider can contain spaces.
+func Parse(args string) (*Request, error) {
+	fields := strings.Fields(args)
+	if len(fields) < 3 {
+		return nil, ErrInvalidFormat
+	}
+
+	amount, err := currency.ParseMoney(strings.Replace(fields[0], ",", ".", 1))
+	if err != nil || !amount.IsPositive() {
+		return nil, errors.Wrapf(ErrInvalidFormat, "invalid amount %s", fields[0])
+	}
+
+	r := &Request{Amount: amount, Provider: strings.Join(fields[3:], " ")}
+	r.From, r.USDMarket = parseCurrency(fields[1], "")
+	r.To, r.USDMarket = parseCurrency(fields[2], r.USDMarket)
+	if r.From == r.To {
+		return nil, errors.Wrapf(ErrInvalidFormat, "same currency %s", r.From)
+	}
+
+	return r, nil
+}
+
+// parseCurrency returns the currency code and the dollar market, if the currency is one.
+func parseCurrency(s string, usdMarket string) (string, string) {
+	c := strings.ToUpper(s)
+	if market.IsUSDMarket(c) {
+		return usd, s
+	}
+	if alias, found := currencyAliases[c]; found {
+		return alias, usdMarket
+	}
+	return c, usdMarket
+}
+
+// Conversion is the best route found for a request.
+type Conversion struct {
+	*Request
+	Result currency.Money
+	Trades []*market.Trade
+}
+
+// Rate returns how many units of To a unit of From buys.
+func (c *Conversion) Rate() currency.Money {
+	return c.Result.Div(c.Amount)
+}
+
+func (c *Conversion) Route() string {
+	return market.Route(c.Trades)
+}
+
+// bestRoute returns the simple path of up to maxHops trades that yields the most units of to.
+func bestRoute(trades []*market.Trade, from string, to string, maxHops int) ([]*market.Trade, currency.Money) {
+	byFrom := make(map[string][]*market.Trade)
+	for _, t := range trades {
+		byFrom[t.From] = append(byFrom[t.From], t)
+	}
+
+	var best []*market.Trade
+	var bestRate currency.Money
+	visited := map[string]bool{from: true}
+	var path []*market.Trade
+	var walk func(current string, rate currency.Money)
+	walk = func(current string, rate currency.Money) {
+		for _, t := range byFrom[current] {
+			if visited[t.To] {
+				continue
+			}
+
+			next := rate.Mul(t.Rate)
+			path = append(path, t)
+			if t.To == to {
+				if next.GreaterThan(bestRate) {
+					best, bestRate = append([]*market.Trade(nil), path...), next
+				}
+			} else if len(path) < maxHops {
+				visited[t.To] = true
+				walk(t.To, next)
+				visited[t.To] = false
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	walk(from, currency.MoneyFromInt(1))
+
+	return best, bestRate
+}
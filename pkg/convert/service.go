@@ -0,0 +1,69 @@
+package convert
+
+import (
+	"context"
+	"strings"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+	"coinbani/pkg/market"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNoRoute = errors.New("no conversion route")
+
+type currencyService interface {
+	ProviderByName(name string) (currency.ProviderInfo, bool)
+	GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices
+}
+
+type service struct {
+	config          *options.ConvertConfig
+	currencyService currencyService
+}
+
+func NewService(c *options.ConvertConfig, cs currencyService) *service {
+	return &service{config: c, currencyService: cs}
+}
+
+// Convert parses the convert command arguments and finds the route with the best rate
+// through the current quotes, using a single provider when one is given.
+func (s *service) Convert(ctx context.Context, args string) (*Conversion, error) {
+	r, err := Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Provider != "" {
+		p, found := s.currencyService.ProviderByName(r.Provider)
+		if !found {
+			return nil, currency.ErrUnknownProvider
+		}
+		r.Provider = p.Label
+	}
+
+	var priceLists []*currency.CurrencyPriceList
+	for _, result := range s.currencyService.GetAllLastPrices(ctx) {
+		if result.Err != nil || (r.Provider != "" && result.ProviderName != r.Provider) {
+			continue
+		}
+		priceLists = append(priceLists, result.PriceList)
+	}
+
+	var trades []*market.Trade
+	for _, t := range market.BuildTrades(priceLists) {
+		// with a dollar market every other way in or out of USD is left out, e.g. through DAI
+		if r.USDMarket != "" && (t.From == usd || t.To == usd) && !strings.EqualFold(t.Market, r.USDMarket) {
+			continue
+		}
+		trades = append(trades, t)
+	}
+
+	route, rate := bestRoute(trades, r.From, r.To, s.config.MaxHops)
+	if route == nil {
+		return nil, ErrNoRoute
+	}
+
+	return &Conversion{Request: r, Result: r.Amount.Mul(rate), Trades: route}, nil
+}
@@ -0,0 +1,124 @@
+package convert
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+type fakeCurrencyService []*currency.ProviderPrices
+
+func (f fakeCurrencyService) ProviderByName(name string) (currency.ProviderInfo, bool) {
+	for _, result := range f {
+		if strings.EqualFold(result.ProviderName, name) {
+			return currency.ProviderInfo{Label: result.ProviderName}, true
+		}
+	}
+	return currency.ProviderInfo{}, false
+}
+
+func (f fakeCurrencyService) GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices {
+	return f
+}
+
+func TestService_Convert(t *testing.T) {
+	prices := func(provider string, p ...*currency.CurrencyPrice) *currency.ProviderPrices {
+		return &currency.ProviderPrices{ProviderName: provider, PriceList: &currency.CurrencyPriceList{ProviderName: provider, Prices: p}}
+	}
+	cs := fakeCurrencyService{
+		prices("Dolar",
+			&currency.CurrencyPrice{Desc: "Blue", BidPrice: currency.MustParseMoney("140"), AskPrice: currency.MustParseMoney("160")},
+			&currency.CurrencyPrice{Desc: "MEP", BidPrice: currency.MustParseMoney("130"), AskPrice: currency.MustParseMoney("150")},
+		),
+		prices("Buenbit",
+			&currency.CurrencyPrice{Desc: "DAI/ARS", BidPrice: currency.MustParseMoney("150"), AskPrice: currency.MustParseMoney("170")},
+			&currency.CurrencyPrice{Desc: "DAI/USD", BidPrice: currency.MustParseMoney("0.96"), AskPrice: currency.MustParseMoney("1.25")},
+			&currency.CurrencyPrice{Desc: "ARS/USD", BidPrice: currency.MustParseMoney("130"), AskPrice: currency.MustParseMoney("155")},
+		),
+		prices("Satoshi Tango", &currency.CurrencyPrice{Desc: "BTC/ARS", BidPrice: currency.MustParseMoney("4000000"), AskPrice: currency.MustParseMoney("5000000")}),
+	}
+
+	tests := []struct {
+		name       string
+		args       string
+		wantResult currency.Money
+		wantRoute  string
+		wantErr    error
+	}{
+		{
+			name:       "buys at the best ask",
+			args:       "15000 ARS USD",
+			wantResult: currency.MustParseMoney("100"),
+			wantRoute:  "ARS → USD (Dolar MEP)",
+		},
+		{
+			name:       "restricted to a dollar market",
+			args:       "16000 pesos blue",
+			wantResult: currency.MustParseMoney("100"),
+			wantRoute:  "ARS → USD (Dolar Blue)",
+		},
+		{
+			name:       "computed dollar paying better than blue",
+			args:       "15500 ARS USD Buenbit",
+			wantResult: currency.MustParseMoney("100"),
+			wantRoute:  "ARS → USD (Buenbit)",
+		},
+		{
+			name:       "sells at the best bid",
+			args:       "100 USD ARS",
+			wantResult: currency.MustParseMoney("14000"),
+			wantRoute:  "USD → ARS (Dolar Blue)",
+		},
+		{
+			name:       "single provider",
+			args:       "100 USD DAI Buenbit",
+			wantResult: currency.MustParseMoney("80"),
+			wantRoute:  "USD → DAI (Buenbit)",
+		},
+		{
+			name:       "multi hop through providers",
+			args:       "100 DAI BTC",
+			wantResult: currency.MustParseMoney("0.003"),
+			wantRoute:  "DAI → ARS (Buenbit) → BTC (Satoshi Tango)",
+		},
+		{
+			name:    "no route in the provider",
+			args:    "1000 ARS BTC Buenbit",
+			wantErr: ErrNoRoute,
+		},
+		{
+			name:    "unknown provider",
+			args:    "1000 ARS USD Ripio",
+			wantErr: currency.ErrUnknownProvider,
+		},
+		{
+			name:    "invalid amount",
+			args:    "mil ARS USD",
+			wantErr: ErrInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(&options.ConvertConfig{MaxHops: 3}, cs).Convert(context.Background(), tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !got.Result.Round(8).Equal(tt.wantResult) {
+				t.Errorf("Convert() result = %v, want %v", got.Result, tt.wantResult)
+			}
+			if got.Route() != tt.wantRoute {
+				t.Errorf("Convert() route = %v, want %v", got.Route(), tt.wantRoute)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package cryptodollar
+
+import (
+	"context"
+	"fmt"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNoCryptoDollars = errors.New("no provider quotes a crypto dollar")
+
+type pricesFetcher interface {
+	GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices
+}
+
+// Table holds a crypto dollar row per provider quoting one followed by the reference dollars.
+type Table struct {
+	Prices []*currency.CurrencyPrice
+	// Unavailable are the providers that couldn't be fetched
+	Unavailable []string
+}
+
+type service struct {
+	currencyService pricesFetcher
+	providerName    string
+	referencePairs  []string
+}
+
+// NewService shows the reference pairs of the dollar provider with the given name along the crypto
+// dollars, the first one is the dollar every row is compared with.
+func NewService(cs pricesFetcher, providerName string, referencePairs []string) *service {
+	return &service{currencyService: cs, providerName: providerName, referencePairs: referencePairs}
+}
+
+// CryptoDollars computes the crypto dollar of every provider, every row shows its percent over the
+// first reference pair when available.
+func (s *service) CryptoDollars(ctx context.Context) (*Table, error) {
+	table := &Table{}
+	var dollars *currency.CurrencyPriceList
+	for _, r := range s.currencyService.GetAllLastPrices(ctx) {
+		if r.Err != nil || r.PriceList == nil {
+			table.Unavailable = append(table.Unavailable, r.ProviderName)
+			continue
+		}
+		if r.ProviderName == s.providerName {
+			dollars = r.PriceList
+			continue
+		}
+
+		cd, found := r.PriceList.CryptoDollar()
+		if !found {
+			continue
+		}
+
+		table.Prices = append(table.Prices, &currency.CurrencyPrice{
+			Desc:     fmt.Sprintf("%s %s", r.ProviderName, cd.Asset),
+			Currency: "ARS",
+			BidPrice: cd.BidPrice,
+			AskPrice: cd.AskPrice,
+		})
+	}
+
+	if len(table.Prices) == 0 {
+		return nil, ErrNoCryptoDollars
+	}
+
+	if dollars == nil || len(s.referencePairs) == 0 {
+		return table, nil
+	}
+
+	for _, pair := range s.referencePairs {
+		if p := dollars.Find(pair); p != nil {
+			table.Prices = append(table.Prices, &currency.CurrencyPrice{
+				Desc:     p.Desc,
+				Currency: p.Currency,
+				BidPrice: p.BidPrice,
+				AskPrice: p.AskPrice,
+			})
+		}
+	}
+
+	// every row needs the percent for the table to be aligned
+	if reference := dollars.Find(s.referencePairs[0]); reference != nil && reference.AskPrice.IsPositive() {
+		for _, p := range table.Prices {
+			p.PercentChange = currency.PercentChange(reference.AskPrice, p.AskPrice).StringSigned(2) + "%"
+		}
+	}
+
+	return table, nil
+}
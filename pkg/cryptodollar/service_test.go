@@ -0,0 +1,82 @@
+package cryptodollar
+
+import (
+	"context"
+	"testing"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+type fakePricesFetcher []*currency.ProviderPrices
+
+func (f fakePricesFetcher) GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices {
+	return f
+}
+
+func priceList(prices ...*currency.CurrencyPrice) *currency.CurrencyPriceList {
+	return &currency.CurrencyPriceList{Prices: prices}
+}
+
+func price(desc string, bid string, ask string) *currency.CurrencyPrice {
+	return &currency.CurrencyPrice{Desc: desc, BidPrice: currency.MustParseMoney(bid), AskPrice: currency.MustParseMoney(ask)}
+}
+
+func TestService_CryptoDollars(t *testing.T) {
+	buenbit := &currency.ProviderPrices{ProviderName: "Buenbit", PriceList: priceList(price("DAI/ARS", "1000", "1100"), price("DAI/USD", "1", "1"))}
+	dollars := &currency.ProviderPrices{ProviderName: "Dolar", PriceList: priceList(
+		price("Oficial", "900", "950"), price("Blue", "1150", "1200"), price("MEP", "990", "1000"), price("CCL", "1010", "1050"),
+	)}
+
+	tests := []struct {
+		name            string
+		fetcher         fakePricesFetcher
+		wantRows        []string
+		wantPercents    []string
+		wantUnavailable []string
+		wantErr         error
+	}{
+		{
+			name:         "compared with the dollars",
+			fetcher:      fakePricesFetcher{buenbit, {ProviderName: "Binance", PriceList: priceList(price("USDT/ARS", "1000", "1100"))}, dollars},
+			wantRows:     []string{"Buenbit DAI", "MEP", "CCL", "Blue"},
+			wantPercents: []string{"+10.00%", "0.00%", "+5.00%", "+20.00%"},
+		},
+		{
+			name:            "dollar provider down",
+			fetcher:         fakePricesFetcher{buenbit, {ProviderName: "Dolar", Err: errors.New("upstream error")}},
+			wantRows:        []string{"Buenbit DAI"},
+			wantPercents:    []string{""},
+			wantUnavailable: []string{"Dolar"},
+		},
+		{
+			name:    "no crypto dollar",
+			fetcher: fakePricesFetcher{dollars},
+			wantErr: ErrNoCryptoDollars,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(tt.fetcher, "Dolar", []string{"MEP", "CCL", "Blue"}).CryptoDollars(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CryptoDollars() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(got.Prices) != len(tt.wantRows) {
+				t.Fatalf("CryptoDollars() returned %d rows, want %d", len(got.Prices), len(tt.wantRows))
+			}
+			for i, p := range got.Prices {
+				if p.Desc != tt.wantRows[i] || p.PercentChange != tt.wantPercents[i] {
+					t.Errorf("CryptoDollars() row = %s %s, want %s %s", p.Desc, p.PercentChange, tt.wantRows[i], tt.wantPercents[i])
+				}
+			}
+			if len(got.Unavailable) != len(tt.wantUnavailable) {
+				t.Errorf("CryptoDollars() unavailable = %v, want %v", got.Unavailable, tt.wantUnavailable)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package currency
+
+import (
+	"context"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/cache"
+
+	"go.uber.org/zap"
+)
+
+const cacheKeyPrefix = "prices:"
+
+type requestGroup interface {
+	Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error)
+}
+
+// cachedProvider decorates a currencyProvider caching its prices for the given TTL.
+// Concurrent fetches on a cache miss are coalesced into a single upstream call.
+//
+// Expired prices are kept after the TTL: within the stale-while-revalidate window
+// they are served right away while being refreshed in the background, and within
+// the stale-if-error window they are served when the upstream fetch fails.
+type cachedProvider struct {
+	provider             currencyProvider
+	cache                cache.Cache
+	group                requestGroup
+	ttl                  time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+	logger               *zap.Logger
+}
+
+// NewCachedProvider wraps p with a cache, a non positive TTL makes every request hit the provider.
+func NewCachedProvider(p currencyProvider, c cache.Cache, ttl time.Duration, cfg *options.ProvidersConfig, l *zap.Logger) *cachedProvider {
+	return &cachedProvider{
+		provider:             p,
+		cache:                c,
+		group:                cache.NewGroup(),
+		ttl:                  ttl,
+		staleWhileRevalidate: cfg.StaleWhileRevalidate,
+		staleIfError:         cfg.StaleIfError,
+		logger:               l,
+	}
+}
+
+func (p *cachedProvider) Info() ProviderInfo {
+	return p.provider.Info()
+}
+
+func (p *cachedProvider) FetchLastPrices(ctx context.Context) (*CurrencyPriceList, error) {
+	key := cacheKeyPrefix + p.Info().Label
+
+	cached := p.getCached(key)
+	if cached != nil {
+		age := time.Since(cached.FetchedAt)
+		if age < p.ttl {
+			return withCacheStatus(cached, CacheStatusHit), nil
+		}
+
+		if age < p.ttl+p.staleWhileRevalidate {
+			go p.refresh(key)
+			return withCacheStatus(cached, CacheStatusStale), nil
+		}
+	}
+
+	priceList, err := p.fetch(ctx, key)
+	if err != nil {
+		if cached != nil && time.Since(cached.FetchedAt) < p.ttl+p.staleIfError {
+			p.logger.Warn("serving stale prices after fetch error", zap.String("key", key), zap.Error(err))
+			return withCacheStatus(cached, CacheStatusStale), nil
+		}
+		return nil, err
+	}
+
+	return withCacheStatus(priceList, CacheStatusMiss), nil
+}
+
+func (p *cachedProvider) getCached(key string) *CurrencyPriceList {
+	v, found := p.cache.Get(key)
+	if !found {
+		return nil
+	}
+	return v.(*CurrencyPriceList)
+}
+
+func (p *cachedProvider) fetch(ctx context.Context, key string) (*CurrencyPriceList, error) {
+	v, err := p.group.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		p.logger.Debug("fetching prices from provider", zap.String("key", key))
+		priceList, err := p.provider.FetchLastPrices(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if priceList.FetchedAt.IsZero() {
+			priceList.FetchedAt = time.Now()
+		}
+
+		if retention := p.retention(); retention > 0 {
+			p.cache.Set(key, priceList, retention)
+		}
+		return priceList, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*CurrencyPriceList), nil
+}
+
+func (p *cachedProvider) refresh(key string) {
+	if _, err := p.fetch(context.Background(), key); err != nil {
+		p.logger.Error("refreshing prices in background", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// retention is how long prices are kept in cache, including the stale windows.
+// Nothing is cached with a non positive TTL, not even for the stale windows.
+func (p *cachedProvider) retention() time.Duration {
+	if p.ttl <= 0 {
+		return 0
+	}
+
+	window := p.staleWhileRevalidate
+	if p.staleIfError > window {
+		window = p.staleIfError
+	}
+	return p.ttl + window
+}
+
+// withCacheStatus returns a copy of the cached price list so callers never modify the cached value.
+func withCacheStatus(priceList *CurrencyPriceList, status CacheStatus) *CurrencyPriceList {
+	c := *priceList
+	c.CacheStatus = status
+	return &c
+}
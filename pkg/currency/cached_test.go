@@ -0,0 +1,156 @@
+package currency
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/cache"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type countingProvider struct {
+	fakeProvider
+	calls int32
+	fail  int32
+}
+
+func (p *countingProvider) FetchLastPrices(ctx context.Context) (*CurrencyPriceList, error) {
+	atomic.AddInt32(&p.calls, 1)
+	if atomic.LoadInt32(&p.fail) == 1 {
+		return nil, errors.New("upstream error")
+	}
+	return p.fakeProvider.FetchLastPrices(ctx)
+}
+
+func newTestCachedProvider(ttl time.Duration, cfg *options.ProvidersConfig) (*cachedProvider, *countingProvider) {
+	p := &countingProvider{fakeProvider: fakeProvider{
+		label:  "test",
+		delay:  20 * time.Millisecond,
+		prices: []*CurrencyPrice{{Desc: "BTC/ARS"}},
+	}}
+	return NewCachedProvider(p, cache.New(), ttl, cfg, zap.NewNop()), p
+}
+
+func Test_cachedProvider_FetchLastPrices(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		requests  int
+		rounds    int
+		wantCalls int32
+	}{
+		{
+			name:      "concurrent requests are coalesced into one upstream call",
+			ttl:       time.Minute,
+			requests:  20,
+			rounds:    1,
+			wantCalls: 1,
+		},
+		{
+			name:      "cached prices are served until the TTL expires",
+			ttl:       time.Minute,
+			requests:  5,
+			rounds:    3,
+			wantCalls: 1,
+		},
+		{
+			name:      "non positive TTL disables caching",
+			ttl:       0,
+			requests:  1,
+			rounds:    3,
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the default stale windows must not keep prices with a non positive TTL
+			cp, p := newTestCachedProvider(tt.ttl, &options.ProvidersConfig{StaleWhileRevalidate: time.Minute, StaleIfError: time.Hour})
+
+			for r := 0; r < tt.rounds; r++ {
+				var wg sync.WaitGroup
+				for i := 0; i < tt.requests; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						if _, err := cp.FetchLastPrices(context.Background()); err != nil {
+							t.Errorf("FetchLastPrices() error = %v", err)
+						}
+					}()
+				}
+				wg.Wait()
+			}
+
+			if got := atomic.LoadInt32(&p.calls); got != tt.wantCalls {
+				t.Errorf("upstream calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_cachedProvider_FetchLastPrices_stale(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *options.ProvidersConfig
+		fail      bool
+		wantStale bool
+		wantErr   bool
+	}{
+		{
+			name:      "expired prices are served stale while revalidating",
+			cfg:       &options.ProvidersConfig{StaleWhileRevalidate: time.Minute},
+			wantStale: true,
+		},
+		{
+			name:      "expired prices are served stale on upstream error",
+			cfg:       &options.ProvidersConfig{StaleIfError: time.Minute},
+			fail:      true,
+			wantStale: true,
+		},
+		{
+			name: "expired prices are refreshed when there is no stale window",
+			cfg:  &options.ProvidersConfig{StaleIfError: time.Minute},
+		},
+		{
+			name:    "upstream error is returned when there is no stale window",
+			cfg:     &options.ProvidersConfig{},
+			fail:    true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ttl := 10 * time.Millisecond
+			cp, p := newTestCachedProvider(ttl, tt.cfg)
+
+			// seed the cache with prices older than the TTL
+			cp.cache.Set(cacheKeyPrefix+"test", &CurrencyPriceList{
+				ProviderName: "test",
+				FetchedAt:    time.Now().Add(-2 * ttl),
+			}, time.Minute)
+
+			if tt.fail {
+				atomic.StoreInt32(&p.fail, 1)
+			}
+
+			got, err := cp.FetchLastPrices(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchLastPrices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.IsStale() != tt.wantStale {
+				t.Errorf("FetchLastPrices() cache status = %v, want stale %v", got.CacheStatus, tt.wantStale)
+			}
+			if got.IsStale() && got.Age() < 2*ttl {
+				t.Errorf("FetchLastPrices() age = %v, want at least %v", got.Age(), 2*ttl)
+			}
+		})
+	}
+}
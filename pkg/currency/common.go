@@ -1,14 +1,75 @@
 package currency
 
+import (
+	"strings"
+	"time"
+)
+
+type CacheStatus string
+
+const (
+	// CacheStatusNone is set on prices that didn't go through a cache
+	CacheStatusNone  CacheStatus = ""
+	CacheStatusMiss  CacheStatus = "miss"
+	CacheStatusHit   CacheStatus = "hit"
+	CacheStatusStale CacheStatus = "stale"
+)
+
 type CurrencyPriceList struct {
 	ProviderName string
 	Prices       []*CurrencyPrice
+	FetchedAt    time.Time
+	CacheStatus  CacheStatus
+	// Gaps are the DollarGaps between the prices of the list
+	Gaps []*Gap
+}
+
+// Age returns how long ago the prices were fetched.
+func (l *CurrencyPriceList) Age() time.Duration {
+	return time.Since(l.FetchedAt)
+}
+
+func (l *CurrencyPriceList) IsStale() bool {
+	return l.CacheStatus == CacheStatusStale
+}
+
+// LastUpdate returns the most recent time reported by the upstream service,
+// falling back to the fetch time when the service doesn't report it.
+func (l *CurrencyPriceList) LastUpdate() time.Time {
+	var last time.Time
+	for _, p := range l.Prices {
+		if p.UpdatedAt.After(last) {
+			last = p.UpdatedAt
+		}
+	}
+
+	if last.IsZero() {
+		return l.FetchedAt
+	}
+	return last
+}
+
+// Find returns the price with the given description ignoring case, or nil if missing.
+func (l *CurrencyPriceList) Find(desc string) *CurrencyPrice {
+	for _, p := range l.Prices {
+		if strings.EqualFold(p.Desc, desc) {
+			return p
+		}
+	}
+	return nil
 }
 
 type CurrencyPrice struct {
 	Desc          string
 	Currency      string
-	BidPrice      float64
-	AskPrice      float64
+	BidPrice      Money
+	AskPrice      Money
 	PercentChange string
+	// RawBidPrice and RawAskPrice are the quotes before the provider fees
+	RawBidPrice Money
+	RawAskPrice Money
+	// UpdatedAt is the quote time reported by the upstream service, if any
+	UpdatedAt time.Time
+	// Source is the URL the quote was fetched from
+	Source string
 }
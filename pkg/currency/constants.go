@@ -1,7 +0,0 @@
-package currency
-
-const (
-	BBProviderLabel       = "Buenbit"
-	SatoshiTProviderLabel = "Satoshi Tango"
-	DollarProviderLabel    = "Dolar"
-)
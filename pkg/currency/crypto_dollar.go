@@ -0,0 +1,33 @@
+package currency
+
+// cryptoDollarAssets are the assets a crypto dollar is derived from in order of preference,
+// stablecoins first since they track the dollar.
+var cryptoDollarAssets = []string{"DAI", "USDC", "USDT", "BTC"}
+
+// CryptoDollar is the ARS price of a dollar implied by the ARS and USD quotes of an asset.
+type CryptoDollar struct {
+	Asset    string
+	BidPrice Money
+	AskPrice Money
+}
+
+// ImpliedDollar derives the ARS price of a dollar from the ARS and USD quotes of the same asset.
+// Selling dollars means buying the asset in USD and selling it in ARS, and the other way around.
+func ImpliedDollar(ars *CurrencyPrice, usd *CurrencyPrice) (bid Money, ask Money) {
+	return ars.BidPrice.Div(usd.AskPrice).Round(2), ars.AskPrice.Div(usd.BidPrice).Round(2)
+}
+
+// CryptoDollar returns the crypto dollar of the price list through the first asset quoted
+// both in ARS and USD, false when the provider doesn't quote any.
+func (l *CurrencyPriceList) CryptoDollar() (*CryptoDollar, bool) {
+	for _, asset := range cryptoDollarAssets {
+		ars, usd := l.Find(asset+"/ARS"), l.Find(asset+"/USD")
+		if ars == nil || usd == nil || !usd.BidPrice.IsPositive() || !usd.AskPrice.IsPositive() {
+			continue
+		}
+
+		bid, ask := ImpliedDollar(ars, usd)
+		return &CryptoDollar{Asset: asset, BidPrice: bid, AskPrice: ask}, true
+	}
+	return nil, false
+}
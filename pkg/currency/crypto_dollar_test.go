@@ -0,0 +1,49 @@
+package currency
+
+import (
+	"testing"
+)
+
+func TestCurrencyPriceList_CryptoDollar(t *testing.T) {
+	price := func(desc string, bid string, ask string) *CurrencyPrice {
+		return &CurrencyPrice{Desc: desc, BidPrice: MustParseMoney(bid), AskPrice: MustParseMoney(ask)}
+	}
+
+	tests := []struct {
+		name      string
+		prices    []*CurrencyPrice
+		want      *CryptoDollar
+		wantFound bool
+	}{
+		{
+			name:      "stablecoin",
+			prices:    []*CurrencyPrice{price("DAI/ARS", "990", "1050"), price("DAI/USD", "0.99", "1.00"), price("BTC/ARS", "60000000", "61000000"), price("BTC/USD", "59000", "60000")},
+			want:      &CryptoDollar{Asset: "DAI", BidPrice: MustParseMoney("990"), AskPrice: MustParseMoney("1060.61")},
+			wantFound: true,
+		},
+		{
+			name:      "BTC when no stablecoin is quoted in dollars",
+			prices:    []*CurrencyPrice{price("DAI/ARS", "990", "1050"), price("BTC/ARS", "59000000", "61000000"), price("BTC/USD", "59000", "61000")},
+			want:      &CryptoDollar{Asset: "BTC", BidPrice: MustParseMoney("967.21"), AskPrice: MustParseMoney("1033.90")},
+			wantFound: true,
+		},
+		{
+			name:   "only ARS quotes",
+			prices: []*CurrencyPrice{price("DAI/ARS", "990", "1050"), price("BTC/ARS", "59000000", "61000000")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := (&CurrencyPriceList{Prices: tt.prices}).CryptoDollar()
+			if found != tt.wantFound {
+				t.Fatalf("CryptoDollar() found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+			if got.Asset != tt.want.Asset || !got.BidPrice.Equal(tt.want.BidPrice) || !got.AskPrice.Equal(tt.want.AskPrice) {
+				t.Errorf("CryptoDollar() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
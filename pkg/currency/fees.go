@@ -0,0 +1,26 @@
+package currency
+
+import (
+	"coinbani/cmd/coinbani/options"
+)
+
+// applyFees returns a copy of the price list with the bid and ask prices worsened by the taker
+// fee and the spread, the original list is left untouched since it may be cached.
+func applyFees(priceList *CurrencyPriceList, fees options.FeeModel) *CurrencyPriceList {
+	markup := MoneyFromFloat(fees.TakerPercent + fees.SpreadPercent).Div(MoneyFromInt(100))
+	bidFactor := MoneyFromInt(1).Sub(markup)
+	askFactor := MoneyFromInt(1).Add(markup)
+
+	c := *priceList
+	c.Prices = make([]*CurrencyPrice, 0, len(priceList.Prices))
+	for _, p := range priceList.Prices {
+		adjusted := *p
+		adjusted.RawBidPrice = p.BidPrice
+		adjusted.RawAskPrice = p.AskPrice
+		adjusted.BidPrice = p.BidPrice.Mul(bidFactor)
+		adjusted.AskPrice = p.AskPrice.Mul(askFactor)
+		c.Prices = append(c.Prices, &adjusted)
+	}
+
+	return &c
+}
@@ -0,0 +1,55 @@
+package currency
+
+import (
+	"testing"
+
+	"coinbani/cmd/coinbani/options"
+)
+
+func Test_applyFees(t *testing.T) {
+	tests := []struct {
+		name    string
+		fees    options.FeeModel
+		wantBid string
+		wantAsk string
+	}{
+		{
+			name:    "no fees",
+			wantBid: "200",
+			wantAsk: "210",
+		},
+		{
+			name:    "spread",
+			fees:    options.FeeModel{SpreadPercent: 1},
+			wantBid: "198",
+			wantAsk: "212.1",
+		},
+		{
+			name:    "taker fee and spread, the withdrawal fee is not charged on quotes",
+			fees:    options.FeeModel{TakerPercent: 0.5, WithdrawalPercent: 2, SpreadPercent: 1},
+			wantBid: "197",
+			wantAsk: "213.15",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priceList := &CurrencyPriceList{
+				ProviderName: "fake",
+				Prices:       []*CurrencyPrice{{Desc: "DAI/ARS", BidPrice: MustParseMoney("200"), AskPrice: MustParseMoney("210")}},
+			}
+
+			got := applyFees(priceList, tt.fees).Prices[0]
+			if !got.BidPrice.Equal(MustParseMoney(tt.wantBid)) || !got.AskPrice.Equal(MustParseMoney(tt.wantAsk)) {
+				t.Errorf("applyFees() = %v/%v, want %v/%v", got.BidPrice, got.AskPrice, tt.wantBid, tt.wantAsk)
+			}
+			if !got.RawBidPrice.Equal(MustParseMoney("200")) || !got.RawAskPrice.Equal(MustParseMoney("210")) {
+				t.Errorf("applyFees() raw = %v/%v, want 200/210", got.RawBidPrice, got.RawAskPrice)
+			}
+
+			original := priceList.Prices[0]
+			if !original.BidPrice.Equal(MustParseMoney("200")) || !original.AskPrice.Equal(MustParseMoney("210")) {
+				t.Errorf("applyFees() modified the original prices to %v/%v", original.BidPrice, original.AskPrice)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package currency
+
+// GapDefinition is the gap between the ask prices of two pairs quoted in the same list.
+type GapDefinition struct {
+	Name string
+	Pair string
+	Base string
+}
+
+// DollarGaps are computed on every list quoting both pairs, i.e. the dollar provider.
+var DollarGaps = []*GapDefinition{
+	{Name: "Blue vs Oficial", Pair: "Blue", Base: "Oficial"},
+	{Name: "MEP vs CCL", Pair: "MEP", Base: "CCL"},
+}
+
+// Gap is how much more expensive a dollar is than the base one.
+type Gap struct {
+	Name    string
+	Price   Money
+	Base    Money
+	Percent Money
+}
+
+func NewGap(name string, price Money, base Money) *Gap {
+	return &Gap{Name: name, Price: price, Base: base, Percent: PercentChange(base, price)}
+}
+
+// computeGaps returns the gaps of the definitions with both pairs quoted in the list.
+func computeGaps(l *CurrencyPriceList, definitions []*GapDefinition) []*Gap {
+	var gaps []*Gap
+	for _, d := range definitions {
+		price, base := l.Find(d.Pair), l.Find(d.Base)
+		if price == nil || base == nil || !price.AskPrice.IsPositive() || !base.AskPrice.IsPositive() {
+			continue
+		}
+		gaps = append(gaps, NewGap(d.Name, price.AskPrice, base.AskPrice))
+	}
+	return gaps
+}
+
+const (
+	// CryptoGapName is the gap between the median crypto dollar of every provider and the MEP.
+	CryptoGapName = "Cripto vs MEP"
+	// CryptoGapBase is the dollar pair the crypto dollar is compared with.
+	CryptoGapBase = "MEP"
+)
+
+// ComputeCryptoGap returns the gap between the median of the crypto dollars of the lists and the
+// MEP quoted in the dollars list, nil when either is missing.
+func ComputeCryptoGap(dollars *CurrencyPriceList, lists []*CurrencyPriceList) *Gap {
+	if dollars == nil {
+		return nil
+	}
+	base := dollars.Find(CryptoGapBase)
+	if base == nil || !base.AskPrice.IsPositive() {
+		return nil
+	}
+
+	var prices []Money
+	for _, l := range lists {
+		if cd, found := l.CryptoDollar(); found {
+			prices = append(prices, cd.AskPrice)
+		}
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	return NewGap(CryptoGapName, Median(prices), base.AskPrice)
+}
@@ -0,0 +1,105 @@
+package currency
+
+import (
+	"testing"
+)
+
+func Test_computeGaps(t *testing.T) {
+	price := func(desc string, ask string) *CurrencyPrice {
+		return &CurrencyPrice{Desc: desc, AskPrice: MustParseMoney(ask)}
+	}
+
+	tests := []struct {
+		name     string
+		prices   []*CurrencyPrice
+		wantGaps map[string]string
+	}{
+		{
+			name:     "dollar provider",
+			prices:   []*CurrencyPrice{price("Oficial", "1000"), price("Blue", "1250"), price("MEP", "1188"), price("CCL", "1200")},
+			wantGaps: map[string]string{"Blue vs Oficial": "25", "MEP vs CCL": "-1"},
+		},
+		{
+			name:     "missing base",
+			prices:   []*CurrencyPrice{price("Blue", "1250"), price("MEP", "1188"), price("CCL", "1200")},
+			wantGaps: map[string]string{"MEP vs CCL": "-1"},
+		},
+		{
+			name:   "crypto provider",
+			prices: []*CurrencyPrice{price("DAI/ARS", "1200"), price("DAI/USD", "1.01")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeGaps(&CurrencyPriceList{Prices: tt.prices}, DollarGaps)
+			if len(got) != len(tt.wantGaps) {
+				t.Fatalf("computeGaps() = %d gaps, want %d", len(got), len(tt.wantGaps))
+			}
+			for _, g := range got {
+				if !g.Percent.Equal(MustParseMoney(tt.wantGaps[g.Name])) {
+					t.Errorf("computeGaps() %s = %v, want %v", g.Name, g.Percent, tt.wantGaps[g.Name])
+				}
+			}
+		})
+	}
+}
+
+func TestComputeCryptoGap(t *testing.T) {
+	list := func(prices map[string]string) *CurrencyPriceList {
+		l := &CurrencyPriceList{}
+		for desc, price := range prices {
+			l.Prices = append(l.Prices, &CurrencyPrice{Desc: desc, BidPrice: MustParseMoney(price), AskPrice: MustParseMoney(price)})
+		}
+		return l
+	}
+	dollars := list(map[string]string{"MEP": "1000", "CCL": "1050"})
+	crypto := []*CurrencyPriceList{
+		list(map[string]string{"DAI/ARS": "1100", "DAI/USD": "1"}),
+		list(map[string]string{"USDT/ARS": "1040", "USDT/USD": "1"}),
+		list(map[string]string{"USDC/ARS": "1300", "USDC/USD": "1"}),
+		list(map[string]string{"BTC/ARS": "100000000"}),
+	}
+
+	tests := []struct {
+		name        string
+		dollars     *CurrencyPriceList
+		lists       []*CurrencyPriceList
+		wantPercent string
+	}{
+		{
+			name:        "median of the providers",
+			dollars:     dollars,
+			lists:       crypto,
+			wantPercent: "10",
+		},
+		{
+			name:        "even count of providers",
+			dollars:     dollars,
+			lists:       crypto[:2],
+			wantPercent: "7",
+		},
+		{
+			name:  "missing MEP",
+			lists: crypto,
+		},
+		{
+			name:    "no crypto dollar",
+			dollars: dollars,
+			lists:   crypto[3:],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeCryptoGap(tt.dollars, tt.lists)
+			if tt.wantPercent == "" {
+				if got != nil {
+					t.Errorf("ComputeCryptoGap() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || !got.Percent.Equal(MustParseMoney(tt.wantPercent)) {
+				t.Errorf("ComputeCryptoGap() = %+v, want %v%%", got, tt.wantPercent)
+			}
+		})
+	}
+}
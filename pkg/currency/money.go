@@ -0,0 +1,164 @@
+package currency
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Money is an exact decimal amount. Prices and every calculation on them use it instead of
+// float64, which is only meant for display purposes such as charts.
+type Money struct {
+	d decimal.Decimal
+}
+
+// NewMoney returns value * 10^exp, e.g. NewMoney(12345, -2) is 123.45.
+func NewMoney(value int64, exp int32) Money {
+	return Money{d: decimal.New(value, exp)}
+}
+
+func MoneyFromInt(value int64) Money {
+	return Money{d: decimal.NewFromInt(value)}
+}
+
+// MoneyFromFloat converts using the shortest decimal representation of the float, so 0.65
+// becomes exactly 0.65. Meant for configuration values, never for prices.
+func MoneyFromFloat(value float64) Money {
+	return Money{d: decimal.NewFromFloat(value)}
+}
+
+// ParseMoney parses a decimal number such as "123.45".
+func ParseMoney(s string) (Money, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Money{}, errors.Wrapf(err, "parsing money %q", s)
+	}
+	return Money{d: d}, nil
+}
+
+// MustParseMoney is like ParseMoney but panics on error, meant for constants and tests.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{d: m.d.Add(o.d)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{d: m.d.Sub(o.d)}
+}
+
+func (m Money) Mul(o Money) Money {
+	return Money{d: m.d.Mul(o.d)}
+}
+
+// Div divides rounding to 16 decimal places, dividing by zero returns zero.
+func (m Money) Div(o Money) Money {
+	if o.d.IsZero() {
+		return Money{}
+	}
+	return Money{d: m.d.Div(o.d)}
+}
+
+func (m Money) Neg() Money {
+	return Money{d: m.d.Neg()}
+}
+
+// Round rounds half away from zero to the given decimal places.
+func (m Money) Round(places int32) Money {
+	return Money{d: m.d.Round(places)}
+}
+
+func (m Money) Cmp(o Money) int {
+	return m.d.Cmp(o.d)
+}
+
+func (m Money) Equal(o Money) bool {
+	return m.d.Equal(o.d)
+}
+
+func (m Money) LessThan(o Money) bool {
+	return m.d.LessThan(o.d)
+}
+
+func (m Money) GreaterThan(o Money) bool {
+	return m.d.GreaterThan(o.d)
+}
+
+func (m Money) IsZero() bool {
+	return m.d.IsZero()
+}
+
+func (m Money) IsPositive() bool {
+	return m.d.IsPositive()
+}
+
+func (m Money) IsNegative() bool {
+	return m.d.IsNegative()
+}
+
+// Float64 returns the nearest float, for display purposes only.
+func (m Money) Float64() float64 {
+	f, _ := m.d.Float64()
+	return f
+}
+
+func (m Money) String() string {
+	return m.d.String()
+}
+
+// StringFixed formats the amount with a fixed number of decimal places, e.g. "123.40".
+func (m Money) StringFixed(places int32) string {
+	return m.d.StringFixed(places)
+}
+
+// StringSigned is like StringFixed with an explicit sign for positive amounts, e.g. "+1.50".
+func (m Money) StringSigned(places int32) string {
+	if m.IsPositive() {
+		return "+" + m.StringFixed(places)
+	}
+	return m.StringFixed(places)
+}
+
+// MarshalJSON encodes the amount as a string so no precision is lost.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return m.d.MarshalJSON()
+}
+
+// UnmarshalJSON accepts both JSON strings and numbers.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if err := m.d.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	// keep zero as the zero value, so decoded amounts compare equal to the ones never set
+	if m.d.IsZero() {
+		*m = Money{}
+	}
+	return nil
+}
+
+// PercentChange returns the change from the reference amount in percent, zero when the reference is zero.
+func PercentChange(from Money, to Money) Money {
+	return to.Sub(from).Div(from).Mul(MoneyFromInt(100))
+}
+
+// Median returns the middle amount, the average of the two middle ones rounded to 2 decimals
+// for an even count. It expects at least one amount and doesn't modify them.
+func Median(amounts []Money) Money {
+	sorted := append([]Money(nil), amounts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return sorted[middle-1].Add(sorted[middle]).Div(MoneyFromInt(2)).Round(2)
+}
@@ -0,0 +1,85 @@
+package currency
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Money
+		want string
+	}{
+		{
+			name: "addition without float error",
+			got:  MustParseMoney("0.1").Add(MustParseMoney("0.2")),
+			want: "0.3",
+		},
+		{
+			name: "tax inclusive price",
+			got:  MustParseMoney("70.72").Mul(MoneyFromFloat(1.65)).Round(2),
+			want: "116.69",
+		},
+		{
+			name: "implied rate rounded half up",
+			got:  MustParseMoney("150.25").Div(MustParseMoney("1.01")).Round(2),
+			want: "148.76",
+		},
+		{
+			name: "division by zero",
+			got:  MoneyFromInt(1).Div(Money{}),
+			want: "0",
+		},
+		{
+			name: "percent change",
+			got:  PercentChange(MoneyFromInt(80), MoneyFromInt(100)),
+			want: "25",
+		},
+		{
+			name: "median of an odd count",
+			got:  Median([]Money{MoneyFromInt(3), MoneyFromInt(1), MoneyFromInt(2)}),
+			want: "2",
+		},
+		{
+			name: "median of an even count",
+			got:  Median([]Money{MustParseMoney("1010"), MustParseMoney("1000.01"), MoneyFromInt(900), MoneyFromInt(2000)}),
+			want: "1005.01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(MustParseMoney(tt.want)) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "string", data: `"87.05"`, want: NewMoney(8705, -2)},
+		{name: "number", data: `87.05`, want: NewMoney(8705, -2)},
+		{name: "zero", data: `"0"`, want: Money{}},
+		{name: "invalid", data: `"abc"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
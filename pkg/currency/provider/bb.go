@@ -1,10 +1,9 @@
 package provider
 
 import (
-	"encoding/json"
-	"math"
-	"net/http"
+	"context"
 	"strings"
+	"time"
 
 	"coinbani/cmd/coinbani/options"
 	"coinbani/pkg/client"
@@ -13,14 +12,17 @@ import (
 	"github.com/pkg/errors"
 )
 
-var parseBBResponseFunc = func(r *http.Response) (interface{}, error) {
-	var bbResponse *BBResponse
-	err := json.NewDecoder(r.Body).Decode(&bbResponse)
-	if err != nil || bbResponse.Object == nil {
-		return nil, errors.Wrap(err, "decoding BB response json")
+const BBProviderLabel = "Buenbit"
+
+var validateBBResponseFunc = func(r *BBResponse) error {
+	if r.Object == nil {
+		return errors.New("empty BB response")
+	}
+	if r.Object.DaiARS == nil || r.Object.DaiUSD == nil || r.Object.BTCARS == nil {
+		return errors.New("incomplete BB response")
 	}
 
-	return bbResponse, nil
+	return nil
 }
 
 type BBResponse struct {
@@ -34,38 +36,52 @@ type BBObject struct {
 }
 
 type BBPrice struct {
-	BidPrice           float64 `json:"purchase_price,string"`
-	BidCurrency        string  `json:"bid_currency"`
-	AskPrice           float64 `json:"selling_price,string"`
-	AskCurrency        string  `json:"ask_currency"`
-	PriceChangePercent string  `json:"price_change_percent"`
-	Currency           string  `json:"currency"`
-	MarketIdentifier   string  `json:"market_identifier"`
+	BidPrice           currency.Money `json:"purchase_price"`
+	BidCurrency        string         `json:"bid_currency"`
+	AskPrice           currency.Money `json:"selling_price"`
+	AskCurrency        string         `json:"ask_currency"`
+	PriceChangePercent string         `json:"price_change_percent"`
+	Currency           string         `json:"currency"`
+	MarketIdentifier   string         `json:"market_identifier"`
 }
 
 type bbProvider struct {
-	restClient client.Http
-	config     *options.ProvidersConfig
+	restClient  client.Http
+	config      *options.ProvidersConfig
+	retryPolicy *client.RetryPolicy
 }
 
 func NewBBProvider(c *options.ProvidersConfig, r client.Http) *bbProvider {
-	return &bbProvider{config: c, restClient: r}
+	return &bbProvider{config: c, restClient: r, retryPolicy: newRetryPolicy(c, c.BBRetryAttempts)}
 }
 
-func (p *bbProvider) FetchLastPrices() ([]*currency.CurrencyPrice, error) {
+func (p *bbProvider) Info() currency.ProviderInfo {
+	return currency.ProviderInfo{
+		Label:       BBProviderLabel,
+		Description: "Cotizaciones de Buenbit",
+		Pairs:       []string{"DAI/ARS", "DAI/USD", "BTC/ARS", "ARS/USD"},
+		Enabled:     p.config.BBEnabled,
+		Fees:        p.config.BBFees,
+	}
+}
+
+func (p *bbProvider) FetchLastPrices(ctx context.Context) (*currency.CurrencyPriceList, error) {
 	var lastPrices []*currency.CurrencyPrice
 
-	req := &client.GetRequestBuilder{
-		Url:           p.config.BBURL,
-		ParseResponse: parseBBResponseFunc,
+	ctx, cancel := context.WithTimeout(ctx, p.config.BBTimeout)
+	defer cancel()
+
+	req := &client.JSONRequest[BBResponse]{
+		Url:         p.config.BBURL,
+		RetryPolicy: p.retryPolicy,
+		Validate:    validateBBResponseFunc,
 	}
 
-	res, err := p.restClient.Get(req)
+	bbResponse, err := client.GetJSON(ctx, p.restClient, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetching prices from BB service")
 	}
-
-	bbResponse := res.(*BBResponse)
+	fetchedAt := time.Now()
 
 	// DAI ARS
 	lastPrices = addCryptocurrencyBBPrice(lastPrices, bbResponse.Object.DaiARS)
@@ -74,24 +90,32 @@ func (p *bbProvider) FetchLastPrices() ([]*currency.CurrencyPrice, error) {
 	// BTC ARS
 	lastPrices = addCryptocurrencyBBPrice(lastPrices, bbResponse.Object.BTCARS)
 	// ARS USD
-	lastPrices = addUSDBPrice(lastPrices, bbResponse)
+	lastPrices = addUSDBPrice(lastPrices)
 
-	return lastPrices, nil
+	for _, price := range lastPrices {
+		price.Source = p.config.BBURL
+	}
+
+	return &currency.CurrencyPriceList{
+		ProviderName: BBProviderLabel,
+		Prices:       lastPrices,
+		FetchedAt:    fetchedAt,
+	}, nil
 }
 
-func addUSDBPrice(lastPrices []*currency.CurrencyPrice, r *BBResponse) []*currency.CurrencyPrice {
-	daiAskPrice := r.Object.DaiARS.AskPrice
-	usdBidPrice := r.Object.DaiUSD.BidPrice
-	daiBidPrice := r.Object.DaiARS.BidPrice
-	usdAskPrice := r.Object.DaiUSD.AskPrice
+// addUSDBPrice adds the ARS price of a dollar implied by the DAI quotes.
+func addUSDBPrice(lastPrices []*currency.CurrencyPrice) []*currency.CurrencyPrice {
+	l := &currency.CurrencyPriceList{Prices: lastPrices}
+	dai, found := l.CryptoDollar()
+	if !found {
+		return lastPrices
+	}
 
-	lastPrices = append(lastPrices, &currency.CurrencyPrice{
+	return append(lastPrices, &currency.CurrencyPrice{
 		Desc:     "ARS/USD",
-		BidPrice: math.Round(daiBidPrice/usdAskPrice*100) / 100,
-		AskPrice: math.Round(daiAskPrice/usdBidPrice*100) / 100,
+		BidPrice: dai.BidPrice,
+		AskPrice: dai.AskPrice,
 	})
-
-	return lastPrices
 }
 
 func addCryptocurrencyBBPrice(lastPrices []*currency.CurrencyPrice, price *BBPrice) []*currency.CurrencyPrice {
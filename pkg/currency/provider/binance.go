@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/client"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+const BinanceP2PProviderLabel = "Binance P2P"
+
+const (
+	binanceP2PAsset = "USDT"
+	binanceP2PFiat  = "ARS"
+	// binanceP2PPageSize is the most ads the search returns, fetched so filtered ads leave enough of them
+	binanceP2PPageSize = 20
+	// binanceP2PBuy are the ads selling USDT, quoting the ask price, and binanceP2PSell the ones buying it
+	binanceP2PBuy   = "BUY"
+	binanceP2PSell  = "SELL"
+	binanceMerchant = "merchant"
+)
+
+var validateBinanceP2PResponseFunc = func(r *binanceP2PResponse) error {
+	if !r.Success {
+		return errors.Errorf("Binance P2P search failed with code %s", r.Code)
+	}
+
+	return nil
+}
+
+type binanceP2PSearch struct {
+	Asset         string   `json:"asset"`
+	Fiat          string   `json:"fiat"`
+	TradeType     string   `json:"tradeType"`
+	Page          int      `json:"page"`
+	Rows          int      `json:"rows"`
+	PayTypes      []string `json:"payTypes"`
+	PublisherType *string  `json:"publisherType"`
+}
+
+type binanceP2PResponse struct {
+	Code    string       `json:"code"`
+	Success bool         `json:"success"`
+	Data    []*binanceAd `json:"data"`
+}
+
+type binanceAd struct {
+	Adv struct {
+		Price            currency.Money `json:"price"`
+		TradableQuantity currency.Money `json:"tradableQuantity"`
+	} `json:"adv"`
+	Advertiser struct {
+		NickName string `json:"nickName"`
+		UserType string `json:"userType"`
+	} `json:"advertiser"`
+}
+
+type binanceP2PProvider struct {
+	config      *options.ProvidersConfig
+	restClient  client.Http
+	retryPolicy *client.RetryPolicy
+}
+
+func NewBinanceP2PProvider(c *options.ProvidersConfig, r client.Http) *binanceP2PProvider {
+	return &binanceP2PProvider{config: c, restClient: r, retryPolicy: newRetryPolicy(c, c.BinanceP2PRetryAttempts)}
+}
+
+func (p *binanceP2PProvider) Info() currency.ProviderInfo {
+	return currency.ProviderInfo{
+		Label:       BinanceP2PProviderLabel,
+		Description: "Cotización de USDT en el P2P de Binance",
+		Pairs:       []string{binanceP2PAsset + "/" + binanceP2PFiat},
+		Enabled:     p.config.BinanceP2PEnabled,
+		Fees:        p.config.BinanceP2PFees,
+	}
+}
+
+func (p *binanceP2PProvider) FetchLastPrices(ctx context.Context) (*currency.CurrencyPriceList, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.config.BinanceP2PTimeout)
+	defer cancel()
+
+	askPrice, err := p.fetchPrice(ctx, binanceP2PBuy)
+	if err != nil {
+		return nil, err
+	}
+
+	bidPrice, err := p.fetchPrice(ctx, binanceP2PSell)
+	if err != nil {
+		return nil, err
+	}
+
+	return &currency.CurrencyPriceList{
+		ProviderName: BinanceP2PProviderLabel,
+		Prices: []*currency.CurrencyPrice{{
+			Desc:     binanceP2PAsset + "/" + binanceP2PFiat,
+			Currency: binanceP2PFiat,
+			BidPrice: bidPrice,
+			AskPrice: askPrice,
+			Source:   p.config.BinanceP2PURL,
+		}},
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// fetchPrice searches the ads of the trade type and returns their median price.
+func (p *binanceP2PProvider) fetchPrice(ctx context.Context, tradeType string) (currency.Money, error) {
+	search := &binanceP2PSearch{
+		Asset:     binanceP2PAsset,
+		Fiat:      binanceP2PFiat,
+		TradeType: tradeType,
+		Page:      1,
+		Rows:      binanceP2PPageSize,
+		PayTypes:  []string{},
+	}
+	if p.config.BinanceP2PMerchantOnly {
+		publisherType := binanceMerchant
+		search.PublisherType = &publisherType
+	}
+
+	req := &client.JSONRequest[binanceP2PResponse]{
+		Url:         p.config.BinanceP2PURL,
+		RetryPolicy: p.retryPolicy,
+		Validate:    validateBinanceP2PResponseFunc,
+	}
+
+	res, err := client.PostJSON(ctx, p.restClient, req, search)
+	if err != nil {
+		return currency.Money{}, errors.Wrapf(err, "fetching %s ads from Binance P2P service", tradeType)
+	}
+
+	minVolume := currency.MoneyFromFloat(p.config.BinanceP2PMinVolume)
+	price, found := medianAdPrice(res.Data, p.config.BinanceP2PTopAds, minVolume, p.config.BinanceP2PMerchantOnly)
+	if !found {
+		return currency.Money{}, errors.Errorf("no Binance P2P %s ads left after filtering", tradeType)
+	}
+
+	return price, nil
+}
+
+// medianAdPrice returns the median price of the first topN ads, in the order returned by the search,
+// with at least minVolume USDT available and published by merchants when merchantOnly.
+// The median keeps a single ad with an outlier price from moving the quote.
+func medianAdPrice(ads []*binanceAd, topN int, minVolume currency.Money, merchantOnly bool) (currency.Money, bool) {
+	var prices []currency.Money
+	for _, ad := range ads {
+		if len(prices) == topN {
+			break
+		}
+		if ad.Adv.TradableQuantity.LessThan(minVolume) || !ad.Adv.Price.IsPositive() {
+			continue
+		}
+		if merchantOnly && ad.Advertiser.UserType != binanceMerchant {
+			continue
+		}
+		prices = append(prices, ad.Adv.Price)
+	}
+
+	if len(prices) == 0 {
+		return currency.Money{}, false
+	}
+
+	return currency.Median(prices), true
+}
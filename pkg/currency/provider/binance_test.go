@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"testing"
+
+	"coinbani/pkg/currency"
+)
+
+func Test_medianAdPrice(t *testing.T) {
+	ads := readFixture[binanceP2PResponse](t, "binance_p2p_buy.json").Data
+
+	type args struct {
+		topN         int
+		minVolume    string
+		merchantOnly bool
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      string
+		wantFound bool
+	}{
+		{
+			name:      "median of an odd number of ads",
+			args:      args{topN: 3, minVolume: "100", merchantOnly: true},
+			want:      "1201",
+			wantFound: true,
+		},
+		{
+			name:      "median of an even number of ads",
+			args:      args{topN: 10, minVolume: "100", merchantOnly: true},
+			want:      "1201.75",
+			wantFound: true,
+		},
+		{
+			name:      "without filters",
+			args:      args{topN: 5, minVolume: "0", merchantOnly: false},
+			want:      "1200",
+			wantFound: true,
+		},
+		{
+			name: "every ad filtered",
+			args: args{topN: 10, minVolume: "10000", merchantOnly: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := medianAdPrice(ads, tt.args.topN, currency.MustParseMoney(tt.args.minVolume), tt.args.merchantOnly)
+			if found != tt.wantFound {
+				t.Fatalf("medianAdPrice() found = %v, want %v", found, tt.wantFound)
+			}
+			if found && !got.Equal(currency.MustParseMoney(tt.want)) {
+				t.Errorf("medianAdPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateBinanceP2PResponseFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		wantErr bool
+	}{
+		{
+			name:    "successful search",
+			fixture: "binance_p2p_buy.json",
+		},
+		{
+			name:    "failed search",
+			fixture: "binance_p2p_failed.json",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBinanceP2PResponseFunc(readFixture[binanceP2PResponse](t, tt.fixture))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBinanceP2PResponseFunc() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package provider
+
+import (
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/client"
+)
+
+// newRetryPolicy builds a provider retry policy sharing the backoff settings of every provider.
+func newRetryPolicy(c *options.ProvidersConfig, maxAttempts int) *client.RetryPolicy {
+	return &client.RetryPolicy{
+		MaxAttempts:     maxAttempts,
+		InitialBackoff:  c.RetryInitialBackoff,
+		MaxBackoff:      c.RetryMaxBackoff,
+		Multiplier:      c.RetryMultiplier,
+		Jitter:          c.RetryJitter,
+		RetryableStatus: c.RetryStatusCodes,
+	}
+}
@@ -1,41 +1,45 @@
 package provider
 
 import (
-	"encoding/json"
-	"math"
-	"net/http"
-	"strconv"
+	"context"
 	"strings"
+	"time"
 
 	"coinbani/cmd/coinbani/options"
 	"coinbani/pkg/client"
 	"coinbani/pkg/currency"
+	"coinbani/pkg/tax"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	DollarProviderLabel = "Dolar"
+	// OfficialDollarPair is the quote the taxed dollars are derived from
+	OfficialDollarPair = "Oficial"
+	BlueDollarPair     = "Blue"
+	MEPDollarPair      = "MEP"
+	CCLDollarPair      = "CCL"
+)
+
 const (
 	dollarOfficial = "Dolar Oficial"
 	dollarBlue     = "Dolar Blue"
 	dollarMEP      = "Dolar Bolsa"
 	dollarCCL      = "Dolar Contado con Liqui"
-	dollarSaving   = "Ahorro"
 )
 
 var namesMap = map[string]string{
-	"Bolsa":             "MEP",
-	"Contado con Liqui": "CCL",
+	"Bolsa":             MEPDollarPair,
+	"Contado con Liqui": CCLDollarPair,
 }
 
-var parseDollarResponseFunc = func(r *http.Response) (interface{}, error) {
-	var dollarResponse *dollarRateResponse
-	err := json.NewDecoder(r.Body).Decode(&dollarResponse)
-	if err != nil || len(*dollarResponse) < 2 {
-		return nil, errors.Wrap(err, "decoding dollar response json")
+var validateDollarResponseFunc = func(r *dollarRateResponse) error {
+	if len(*r) < 2 {
+		return errors.New("incomplete dollar response")
 	}
-	defer r.Body.Close()
 
-	return dollarResponse, nil
+	return nil
 }
 
 type dollarRateResponse []struct {
@@ -49,40 +53,67 @@ type dollarPrice struct {
 	PercentChange string `json:"variacion"`
 }
 
+type taxRules interface {
+	RowNames() []string
+	Apply(base currency.Money, at time.Time) []*tax.Breakdown
+}
+
 type dollarProvider struct {
-	config     *options.ProvidersConfig
-	restClient client.Http
+	config      *options.ProvidersConfig
+	restClient  client.Http
+	retryPolicy *client.RetryPolicy
+	taxRules    taxRules
 }
 
-func NewDollarProvider(c *options.ProvidersConfig, r client.Http) *dollarProvider {
-	return &dollarProvider{config: c, restClient: r}
+func NewDollarProvider(c *options.ProvidersConfig, r client.Http, t taxRules) *dollarProvider {
+	return &dollarProvider{config: c, restClient: r, retryPolicy: newRetryPolicy(c, c.DollarRetryAttempts), taxRules: t}
 }
 
-func (d *dollarProvider) FetchLastPrices() ([]*currency.CurrencyPrice, error) {
-	req := &client.GetRequestBuilder{
-		Url:           d.config.DollarURL,
-		ParseResponse: parseDollarResponseFunc,
+func (d *dollarProvider) Info() currency.ProviderInfo {
+	return currency.ProviderInfo{
+		Label:       DollarProviderLabel,
+		Description: "Cotizaciones del dólar",
+		Pairs:       append([]string{OfficialDollarPair, BlueDollarPair, MEPDollarPair, CCLDollarPair}, d.taxRules.RowNames()...),
+		Enabled:     d.config.DollarEnabled,
+		Fees:        d.config.DollarFees,
 	}
+}
 
-	res, err := d.restClient.Get(req)
+func (d *dollarProvider) FetchLastPrices(ctx context.Context) (*currency.CurrencyPriceList, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.config.DollarTimeout)
+	defer cancel()
+
+	req := &client.JSONRequest[dollarRateResponse]{
+		Url:         d.config.DollarURL,
+		RetryPolicy: d.retryPolicy,
+		Validate:    validateDollarResponseFunc,
+	}
+
+	dollarResponse, err := client.GetJSON(ctx, d.restClient, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetching prices from dollar service")
 	}
+	fetchedAt := time.Now()
 
-	dollarResponse := res.(*dollarRateResponse)
+	var lastPrices []*currency.CurrencyPrice
+	for _, p := range filterPrices(*dollarResponse) {
+		lastPrices = addDollarPrices(lastPrices, p)
+	}
 
-	prices := filterPrices(*dollarResponse)
-	prices, err = d.addDollarSaving(prices)
+	lastPrices, err = d.addTaxedPrices(lastPrices, fetchedAt)
 	if err != nil {
-		return nil, errors.Wrap(err, "addding dollar saving")
+		return nil, errors.Wrap(err, "adding taxed dollars")
 	}
 
-	var lastPrices []*currency.CurrencyPrice
-	for _, p := range prices {
-		lastPrices = addDollarPrices(lastPrices, p)
+	for _, price := range lastPrices {
+		price.Source = d.config.DollarURL
 	}
 
-	return lastPrices, nil
+	return &currency.CurrencyPriceList{
+		ProviderName: DollarProviderLabel,
+		Prices:       lastPrices,
+		FetchedAt:    fetchedAt,
+	}, nil
 }
 
 func filterPrices(response dollarRateResponse) []dollarPrice {
@@ -95,11 +126,13 @@ func filterPrices(response dollarRateResponse) []dollarPrice {
 	return prices
 }
 
-func (d *dollarProvider) addDollarSaving(prices []dollarPrice) ([]dollarPrice, error) {
-	var official *dollarPrice
-	for _, p := range prices {
-		if p.Name == dollarOfficial {
-			official = &p
+// addTaxedPrices adds the dollars derived from the official ask price by the tax rules, e.g. Ahorro.
+// Taxes are only paid when buying, so these rows have no bid price.
+func (d *dollarProvider) addTaxedPrices(lastPrices []*currency.CurrencyPrice, at time.Time) ([]*currency.CurrencyPrice, error) {
+	var official *currency.CurrencyPrice
+	for _, p := range lastPrices {
+		if p.Desc == OfficialDollarPair {
+			official = p
 			break
 		}
 	}
@@ -108,38 +141,25 @@ func (d *dollarProvider) addDollarSaving(prices []dollarPrice) ([]dollarPrice, e
 		return nil, errors.New("official dollar not found in list")
 	}
 
-	b := strings.Replace(official.BidPrice, ",", ".", 1)
-	bidPrice, err := strconv.ParseFloat(b, 64)
-	if err != nil {
-		return nil, errors.New("error parsing official dollar bid price")
+	for _, b := range d.taxRules.Apply(official.AskPrice, at) {
+		lastPrices = append(lastPrices, &currency.CurrencyPrice{
+			Desc:          b.Row,
+			Currency:      "USD",
+			AskPrice:      b.Total,
+			PercentChange: official.PercentChange,
+		})
 	}
 
-	a := strings.Replace(official.AskPrice, ",", ".", 1)
-	askPrice, err := strconv.ParseFloat(a, 64)
-	if err != nil {
-		return nil, errors.New("error parsing official dollar ask price")
-	}
-
-	savingDollar := dollarPrice{
-		Name:          dollarSaving,
-		BidPrice:      strconv.FormatFloat(bidPrice*d.config.DollarSavingTax, 'f', 2, 64),
-		AskPrice:      strconv.FormatFloat(askPrice*d.config.DollarSavingTax, 'f', 2, 64),
-		PercentChange: official.PercentChange,
-	}
-
-	prices = append(prices, savingDollar)
-	return prices, nil
+	return lastPrices, nil
 }
 
 func addDollarPrices(lastPrices []*currency.CurrencyPrice, price dollarPrice) []*currency.CurrencyPrice {
-	bidStr := replaceComa(price.BidPrice)
-	bidPrice, err := strconv.ParseFloat(bidStr, 32)
+	bidPrice, err := currency.ParseMoney(replaceComa(price.BidPrice))
 	if err != nil {
 		return lastPrices
 	}
 
-	askStr := replaceComa(price.AskPrice)
-	askPrice, err := strconv.ParseFloat(askStr, 32)
+	askPrice, err := currency.ParseMoney(replaceComa(price.AskPrice))
 	if err != nil {
 		return lastPrices
 	}
@@ -147,8 +167,8 @@ func addDollarPrices(lastPrices []*currency.CurrencyPrice, price dollarPrice) []
 	lastPrices = append(lastPrices, &currency.CurrencyPrice{
 		Desc:          formatDollarName(price.Name),
 		Currency:      "USD",
-		BidPrice:      math.Round(bidPrice*100) / 100,
-		AskPrice:      math.Round(askPrice*100) / 100,
+		BidPrice:      bidPrice.Round(2),
+		AskPrice:      askPrice.Round(2),
 		PercentChange: formatPercent(price.PercentChange),
 	})
 
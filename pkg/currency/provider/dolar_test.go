@@ -23,15 +23,15 @@ func Test_addDollarPrices(t *testing.T) {
 				lastPrices: []*currency.CurrencyPrice{
 					{
 						Desc:          "Oficial",
-						BidPrice:      float64(65.72),
-						AskPrice:      float64(70.72),
+						BidPrice:      currency.MustParseMoney("65.72"),
+						AskPrice:      currency.MustParseMoney("70.72"),
 						Currency:      "USD",
 						PercentChange: "+0,040",
 					},
 					{
 						Desc:          "Blue",
-						BidPrice:      float64(115.00),
-						AskPrice:      float64(125.00),
+						BidPrice:      currency.MustParseMoney("115.00"),
+						AskPrice:      currency.MustParseMoney("125.00"),
 						Currency:      "USD",
 						PercentChange: "+0,810",
 					},
@@ -47,22 +47,22 @@ func Test_addDollarPrices(t *testing.T) {
 				{
 					Desc:          "Oficial",
 					Currency:      "USD",
-					BidPrice:      float64(65.72),
-					AskPrice:      float64(70.72),
+					BidPrice:      currency.MustParseMoney("65.72"),
+					AskPrice:      currency.MustParseMoney("70.72"),
 					PercentChange: "+0,040",
 				},
 				{
 					Desc:          "Blue",
 					Currency:      "USD",
-					BidPrice:      float64(115.00),
-					AskPrice:      float64(125.00),
+					BidPrice:      currency.MustParseMoney("115.00"),
+					AskPrice:      currency.MustParseMoney("125.00"),
 					PercentChange: "+0,810",
 				},
 				{
 					Desc:          "Ahorro",
 					Currency:      "USD",
-					BidPrice:      float64(87.05),
-					AskPrice:      float64(91.13),
+					BidPrice:      currency.MustParseMoney("87.05"),
+					AskPrice:      currency.MustParseMoney("91.13"),
 					PercentChange: "-0,040",
 				},
 			},
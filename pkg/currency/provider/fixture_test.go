@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readFixture decodes the JSON response stored in testdata.
+func readFixture[T any](t *testing.T, name string) *T {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	v := new(T)
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decoding fixture: %v", err)
+	}
+	return v
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/client"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+const RipioProviderLabel = "Ripio"
+
+// ripioPairs are the tickers shown, in the Ripio pair format
+var ripioPairs = []string{"BTC_ARS", "ETH_ARS", "DAI_ARS", "USDC_ARS"}
+
+var validateRipioResponseFunc = func(r *ripioResponse) error {
+	for _, pair := range ripioPairs {
+		if r.find(pair) == nil {
+			return errors.Errorf("incomplete Ripio response, %s missing", pair)
+		}
+	}
+
+	return nil
+}
+
+type ripioResponse struct {
+	Data []*ripioTicker `json:"data"`
+}
+
+func (r *ripioResponse) find(pair string) *ripioTicker {
+	for _, t := range r.Data {
+		if t.Pair == pair {
+			return t
+		}
+	}
+	return nil
+}
+
+type ripioTicker struct {
+	Pair          string         `json:"pair"`
+	BidPrice      currency.Money `json:"bid"`
+	AskPrice      currency.Money `json:"ask"`
+	PercentChange string         `json:"price_change_percent_24h"`
+	Date          time.Time      `json:"date"`
+}
+
+type ripioProvider struct {
+	config      *options.ProvidersConfig
+	restClient  client.Http
+	retryPolicy *client.RetryPolicy
+}
+
+func NewRipioProvider(c *options.ProvidersConfig, r client.Http) *ripioProvider {
+	return &ripioProvider{config: c, restClient: r, retryPolicy: newRetryPolicy(c, c.RipioRetryAttempts)}
+}
+
+func (p *ripioProvider) Info() currency.ProviderInfo {
+	var pairs []string
+	for _, pair := range ripioPairs {
+		pairs = append(pairs, formatRipioPair(pair))
+	}
+
+	return currency.ProviderInfo{
+		Label:       RipioProviderLabel,
+		Description: "Cotizaciones de Ripio",
+		Pairs:       pairs,
+		Enabled:     p.config.RipioEnabled,
+		Fees:        p.config.RipioFees,
+	}
+}
+
+func (p *ripioProvider) FetchLastPrices(ctx context.Context) (*currency.CurrencyPriceList, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.config.RipioTimeout)
+	defer cancel()
+
+	req := &client.JSONRequest[ripioResponse]{
+		Url:         p.config.RipioURL,
+		RetryPolicy: p.retryPolicy,
+		Validate:    validateRipioResponseFunc,
+	}
+
+	ripioResponse, err := client.GetJSON(ctx, p.restClient, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching prices from Ripio service")
+	}
+
+	lastPrices := addRipioPrices(nil, ripioResponse)
+	for _, price := range lastPrices {
+		price.Source = p.config.RipioURL
+	}
+
+	return &currency.CurrencyPriceList{
+		ProviderName: RipioProviderLabel,
+		Prices:       lastPrices,
+		FetchedAt:    time.Now(),
+	}, nil
+}
+
+// addRipioPrices adds the shown pairs in the ripioPairs order, the response must quote every one.
+func addRipioPrices(lastPrices []*currency.CurrencyPrice, r *ripioResponse) []*currency.CurrencyPrice {
+	for _, pair := range ripioPairs {
+		t := r.find(pair)
+		desc := formatRipioPair(t.Pair)
+		lastPrices = append(lastPrices, &currency.CurrencyPrice{
+			Desc:          desc,
+			Currency:      desc[strings.Index(desc, "/")+1:],
+			BidPrice:      t.BidPrice,
+			AskPrice:      t.AskPrice,
+			PercentChange: formatPercent(t.PercentChange),
+			UpdatedAt:     t.Date,
+		})
+	}
+
+	return lastPrices
+}
+
+// formatRipioPair turns BTC_ARS into BTC/ARS.
+func formatRipioPair(pair string) string {
+	return strings.Replace(pair, "_", "/", 1)
+}
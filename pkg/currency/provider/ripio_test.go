@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"coinbani/pkg/currency"
+)
+
+func Test_addRipioPrices(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.RFC3339, s)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		fixture string
+		want    []*currency.CurrencyPrice
+	}{
+		{
+			name:    "add every shown pair in order",
+			fixture: "ripio_tickers.json",
+			want: []*currency.CurrencyPrice{
+				{
+					Desc:          "BTC/ARS",
+					Currency:      "ARS",
+					BidPrice:      currency.MustParseMoney("104210000.5"),
+					AskPrice:      currency.MustParseMoney("105950000"),
+					PercentChange: "-1.23",
+					UpdatedAt:     date("2024-11-05T14:32:10.412Z"),
+				},
+				{
+					Desc:          "ETH/ARS",
+					Currency:      "ARS",
+					BidPrice:      currency.MustParseMoney("3850000"),
+					AskPrice:      currency.MustParseMoney("3910000"),
+					PercentChange: "+0.85",
+					UpdatedAt:     date("2024-11-05T14:31:58.120Z"),
+				},
+				{
+					Desc:          "DAI/ARS",
+					Currency:      "ARS",
+					BidPrice:      currency.MustParseMoney("1165.2"),
+					AskPrice:      currency.MustParseMoney("1187.5"),
+					PercentChange: "+0.12",
+					UpdatedAt:     date("2024-11-05T14:32:01.006Z"),
+				},
+				{
+					Desc:          "USDC/ARS",
+					Currency:      "ARS",
+					BidPrice:      currency.MustParseMoney("1168"),
+					AskPrice:      currency.MustParseMoney("1189.9"),
+					PercentChange: "-0.05",
+					UpdatedAt:     date("2024-11-05T14:32:05.771Z"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := addRipioPrices(nil, readFixture[ripioResponse](t, tt.fixture))
+			if len(got) != len(tt.want) {
+				t.Fatalf("addRipioPrices() returned %d prices, want %d", len(got), len(tt.want))
+			}
+			for i, p := range got {
+				w := tt.want[i]
+				if p.Desc != w.Desc || p.Currency != w.Currency || p.PercentChange != w.PercentChange || !p.UpdatedAt.Equal(w.UpdatedAt) ||
+					!p.BidPrice.Equal(w.BidPrice) || !p.AskPrice.Equal(w.AskPrice) {
+					t.Errorf("addRipioPrices() = %+v, want %+v", p, w)
+				}
+			}
+		})
+	}
+}
+
+func Test_validateRipioResponseFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		wantErr bool
+	}{
+		{
+			name:    "every pair quoted",
+			fixture: "ripio_tickers.json",
+		},
+		{
+			name:    "missing pairs",
+			fixture: "ripio_tickers_incomplete.json",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRipioResponseFunc(readFixture[ripioResponse](t, tt.fixture))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRipioResponseFunc() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_formatRipioPair(t *testing.T) {
+	tests := []struct {
+		name string
+		pair string
+		want string
+	}{
+		{name: "format ripio pair", pair: "USDC_ARS", want: "USDC/ARS"},
+		{name: "format already formatted pair shouldn't fail", pair: "BTC/ARS", want: "BTC/ARS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRipioPair(tt.pair); got != tt.want {
+				t.Errorf("formatRipioPair() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,9 @@
 package provider
 
 import (
-	"encoding/json"
-	"net/http"
+	"context"
 	"strings"
+	"time"
 
 	"coinbani/cmd/coinbani/options"
 	"coinbani/pkg/client"
@@ -12,15 +12,15 @@ import (
 	"github.com/pkg/errors"
 )
 
-var parseSatoshiTResponseFunc = func(r *http.Response) (interface{}, error) {
-	var satoshiTResponse satoshiResponse
-	err := json.NewDecoder(r.Body).Decode(&satoshiTResponse)
-	if err != nil {
-		return nil, errors.Wrap(err, "decoding Satoshi response json")
+const SatoshiTProviderLabel = "Satoshi Tango"
+
+var validateSatoshiTResponseFunc = func(r *satoshiResponse) error {
+	ticker := r.Data.Ticker
+	if ticker.BTC == nil || ticker.DAI == nil || ticker.ETH == nil {
+		return errors.New("incomplete Satoshi response")
 	}
-	defer r.Body.Close()
 
-	return satoshiTResponse, nil
+	return nil
 }
 
 type satoshiResponse struct {
@@ -38,51 +38,70 @@ type satoshiTicker struct {
 }
 
 type satoshiPrice struct {
-	BidPrice float64 `json:"bid"`
-	AskPrice float64 `json:"ask"`
+	BidPrice  currency.Money `json:"bid"`
+	AskPrice  currency.Money `json:"ask"`
+	Timestamp int64          `json:"timestamp"`
 }
 
 type satoshiTProvider struct {
-	config     *options.ProvidersConfig
-	restClient client.Http
+	config      *options.ProvidersConfig
+	restClient  client.Http
+	retryPolicy *client.RetryPolicy
 }
 
 func NewSatoshiTProvider(c *options.ProvidersConfig, r client.Http) *satoshiTProvider {
-	return &satoshiTProvider{config: c, restClient: r}
+	return &satoshiTProvider{config: c, restClient: r, retryPolicy: newRetryPolicy(c, c.SatoshiTRetryAttempts)}
 }
 
-func (p *satoshiTProvider) FetchLastPrices() ([]*currency.CurrencyPrice, error) {
+func (p *satoshiTProvider) Info() currency.ProviderInfo {
+	return currency.ProviderInfo{
+		Label:       SatoshiTProviderLabel,
+		Description: "Cotizaciones de SatoshiTango",
+		Pairs:       []string{"DAI/ARS", "BTC/ARS", "ETH/ARS", "DAI/USD", "BTC/USD", "ETH/USD"},
+		Enabled:     p.config.SatoshiTEnabled,
+		Fees:        p.config.SatoshiTFees,
+	}
+}
+
+func (p *satoshiTProvider) FetchLastPrices(ctx context.Context) (*currency.CurrencyPriceList, error) {
 	var lastPrices []*currency.CurrencyPrice
 	var err error
 
-	// USD
-	lastPrices, err = p.fetchPricesForCurrency("ARS", p.config.SatoshiARSURL, lastPrices)
+	ctx, cancel := context.WithTimeout(ctx, p.config.SatoshiTTimeout)
+	defer cancel()
+
+	// ARS
+	lastPrices, err = p.fetchPricesForCurrency(ctx, "ARS", p.config.SatoshiARSURL, lastPrices)
 	if err != nil {
 		return nil, err
 	}
 
 	// USD
-	lastPrices, err = p.fetchPricesForCurrency("USD", p.config.SatoshiUSDURL, lastPrices)
+	lastPrices, err = p.fetchPricesForCurrency(ctx, "USD", p.config.SatoshiUSDURL, lastPrices)
 	if err != nil {
 		return nil, err
 	}
 
-	return lastPrices, nil
+	return &currency.CurrencyPriceList{
+		ProviderName: SatoshiTProviderLabel,
+		Prices:       lastPrices,
+		FetchedAt:    time.Now(),
+	}, nil
 }
 
-func (p *satoshiTProvider) fetchPricesForCurrency(currency string, fetchURL string, lastPrices []*currency.CurrencyPrice) ([]*currency.CurrencyPrice, error) {
-	req := &client.GetRequestBuilder{
-		Url:           fetchURL,
-		ParseResponse: parseSatoshiTResponseFunc,
+func (p *satoshiTProvider) fetchPricesForCurrency(ctx context.Context, currency string, fetchURL string, lastPrices []*currency.CurrencyPrice) ([]*currency.CurrencyPrice, error) {
+	req := &client.JSONRequest[satoshiResponse]{
+		Url:         fetchURL,
+		RetryPolicy: p.retryPolicy,
+		Validate:    validateSatoshiTResponseFunc,
 	}
 
-	res, err := p.restClient.Get(req)
+	satoshiTResponse, err := client.GetJSON(ctx, p.restClient, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetching prices from SatoshiT service")
 	}
 
-	satoshiTResponse := res.(satoshiResponse)
-
+	fetched := len(lastPrices)
 	// DAI
 	lastPrices = addCryptocurrencySTPrice(lastPrices, satoshiTResponse.Data.Ticker.DAI, "DAI", currency)
 	// BTC
@@ -90,18 +109,27 @@ func (p *satoshiTProvider) fetchPricesForCurrency(currency string, fetchURL stri
 	// ETH
 	lastPrices = addCryptocurrencySTPrice(lastPrices, satoshiTResponse.Data.Ticker.ETH, "ETH", currency)
 
+	for _, price := range lastPrices[fetched:] {
+		price.Source = fetchURL
+	}
+
 	return lastPrices, nil
 }
 
 func addCryptocurrencySTPrice(lastPrices []*currency.CurrencyPrice, price *satoshiPrice, bidCurrency string, askCurrency string) []*currency.CurrencyPrice {
 	desc := strings.ToUpper(bidCurrency) + "/" + strings.ToUpper(askCurrency)
 
-	lastPrices = append(lastPrices, &currency.CurrencyPrice{
+	p := &currency.CurrencyPrice{
 		Desc:     desc,
 		Currency: askCurrency,
-		BidPrice: price.BidPrice * 0.99,
-		AskPrice: price.AskPrice * 1.01,
-	})
+		BidPrice: price.BidPrice,
+		AskPrice: price.AskPrice,
+	}
+	if price.Timestamp > 0 {
+		p.UpdatedAt = time.Unix(price.Timestamp, 0)
+	}
+
+	lastPrices = append(lastPrices, p)
 
 	return lastPrices
 }
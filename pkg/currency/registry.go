@@ -0,0 +1,81 @@
+package currency
+
+import (
+	"sync"
+
+	"coinbani/cmd/coinbani/options"
+
+	"github.com/pkg/errors"
+)
+
+// ProviderInfo describes a price provider registered in the service.
+type ProviderInfo struct {
+	Label       string
+	Description string
+	Pairs       []string
+	Enabled     bool
+	// Fees are charged by the service on every quote of the provider
+	Fees options.FeeModel
+}
+
+type registry struct {
+	lock      sync.RWMutex
+	providers []currencyProvider
+	byLabel   map[string]currencyProvider
+}
+
+func NewRegistry() *registry {
+	return &registry{byLabel: make(map[string]currencyProvider)}
+}
+
+// Register adds the providers to the registry under the label returned by their Info.
+// Providers are listed in registration order.
+func (r *registry) Register(providers ...currencyProvider) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, p := range providers {
+		info := p.Info()
+		if info.Label == "" {
+			return errors.New("provider label is empty")
+		}
+
+		if _, found := r.byLabel[info.Label]; found {
+			return errors.Errorf("provider %s already registered", info.Label)
+		}
+
+		r.providers = append(r.providers, p)
+		r.byLabel[info.Label] = p
+	}
+
+	return nil
+}
+
+// Get returns the enabled provider registered with the given label.
+func (r *registry) Get(label string) (currencyProvider, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	p, found := r.byLabel[label]
+	if !found || !p.Info().Enabled {
+		return nil, false
+	}
+
+	return p, true
+}
+
+// Providers returns the info of every enabled provider.
+func (r *registry) Providers() []ProviderInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	infos := make([]ProviderInfo, 0, len(r.providers))
+	for _, p := range r.providers {
+		info := p.Info()
+		if info.Enabled {
+			infos = append(infos, info)
+		}
+	}
+
+	return infos
+}
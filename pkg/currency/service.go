@@ -1,64 +1,106 @@
 package currency
 
 import (
+	"context"
+	"strings"
+	"sync"
+
+	"coinbani/cmd/coinbani/options"
+
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrFetchTimeout    = errors.New("provider fetch timed out")
+	ErrUnknownProvider = errors.New("unknown provider")
+)
+
 type currencyProvider interface {
-	FetchLastPrices() ([]*CurrencyPrice, error)
+	Info() ProviderInfo
+	FetchLastPrices(ctx context.Context) (*CurrencyPriceList, error)
+}
+
+// ProviderPrices holds the outcome of fetching the prices of a single provider.
+type ProviderPrices struct {
+	ProviderName string
+	PriceList    *CurrencyPriceList
+	Err          error
 }
 
 type service struct {
-	bbProvider     currencyProvider
-	stProvider     currencyProvider
-	dollarProvider currencyProvider
-	logger         *zap.Logger
+	config   *options.ProvidersConfig
+	registry *registry
+	logger   *zap.Logger
 }
 
-func NewService(bb currencyProvider, st currencyProvider, dollar currencyProvider, l *zap.Logger) *service {
-	return &service{bbProvider: bb, stProvider: st, dollarProvider: dollar, logger: l}
+func NewService(c *options.ProvidersConfig, r *registry, l *zap.Logger) *service {
+	return &service{config: c, registry: r, logger: l}
 }
 
-func (s *service) GetLastPrices(providerName string) (*CurrencyPriceList, error) {
-	switch providerName {
-	case BBProviderLabel:
-		return s.getBBPrices()
-	case SatoshiTProviderLabel:
-		return s.getSatoshiTPrices()
-	case DollarProviderLabel:
-		return s.getDollarPrices()
-	default:
-		return nil, errors.New("unknown provider")
+func (s *service) Providers() []ProviderInfo {
+	return s.registry.Providers()
+}
+
+// ProviderByName finds an enabled provider by its label ignoring case.
+func (s *service) ProviderByName(name string) (ProviderInfo, bool) {
+	for _, p := range s.registry.Providers() {
+		if strings.EqualFold(p.Label, name) {
+			return p, true
+		}
 	}
+	return ProviderInfo{}, false
 }
 
-func (s *service) getBBPrices() (*CurrencyPriceList, error) {
-	// Fetch BB prices
-	bbLastPrices, err := s.bbProvider.FetchLastPrices()
+func (s *service) GetLastPrices(ctx context.Context, providerName string) (*CurrencyPriceList, error) {
+	p, found := s.registry.Get(providerName)
+	if !found {
+		return nil, ErrUnknownProvider
+	}
+
+	priceList, err := p.FetchLastPrices(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "fetching BB prices")
+		return nil, errors.Wrapf(err, "fetching %s prices", providerName)
 	}
 
-	return &CurrencyPriceList{ProviderName: BBProviderLabel, Prices: bbLastPrices}, nil
+	priceList = applyFees(priceList, p.Info().Fees)
+	priceList.Gaps = computeGaps(priceList, DollarGaps)
+	return priceList, nil
 }
 
-func (s *service) getSatoshiTPrices() (*CurrencyPriceList, error) {
-	// Fetch SatoshiT prices
-	stLastPrices, err := s.stProvider.FetchLastPrices()
-	if err != nil {
-		return nil, errors.Wrap(err, "fetching SatoshiT prices")
+// GetAllLastPrices fetches the prices of every enabled provider concurrently.
+// Providers that fail or exceed the fetch timeout are returned with their error,
+// results keep the registration order.
+func (s *service) GetAllLastPrices(ctx context.Context) []*ProviderPrices {
+	providers := s.registry.Providers()
+	results := make([]*ProviderPrices, len(providers))
+
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		go func(i int, providerName string) {
+			defer wg.Done()
+			results[i] = s.getLastPricesWithTimeout(ctx, providerName)
+		}(i, p.Label)
 	}
+	wg.Wait()
 
-	return &CurrencyPriceList{ProviderName: SatoshiTProviderLabel, Prices: stLastPrices}, nil
+	return results
 }
 
-func (s *service) getDollarPrices() (*CurrencyPriceList, error) {
-	// Fetch Dollar prices
-	dollarLastPrices, err := s.dollarProvider.FetchLastPrices()
+func (s *service) getLastPricesWithTimeout(ctx context.Context, providerName string) *ProviderPrices {
+	ctx, cancel := context.WithTimeout(ctx, s.config.FetchTimeout)
+	defer cancel()
+
+	priceList, err := s.GetLastPrices(ctx, providerName)
 	if err != nil {
-		return nil, errors.Wrap(err, "fetching Dollar prices")
+		if ctx.Err() == context.DeadlineExceeded {
+			s.logger.Warn("provider fetch timed out", zap.String("provider", providerName))
+			err = errors.Wrapf(ErrFetchTimeout, "fetching %s prices", providerName)
+		} else {
+			s.logger.Error("getting prices", zap.String("provider", providerName), zap.Error(err))
+		}
 	}
 
-	return &CurrencyPriceList{ProviderName: DollarProviderLabel, Prices: dollarLastPrices}, nil
+	return &ProviderPrices{ProviderName: providerName, PriceList: priceList, Err: err}
 }
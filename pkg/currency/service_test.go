@@ -0,0 +1,85 @@
+package currency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type fakeProvider struct {
+	label  string
+	delay  time.Duration
+	prices []*CurrencyPrice
+	err    error
+}
+
+func (p *fakeProvider) Info() ProviderInfo {
+	return ProviderInfo{Label: p.label, Enabled: true}
+}
+
+func (p *fakeProvider) FetchLastPrices(ctx context.Context) (*CurrencyPriceList, error) {
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &CurrencyPriceList{ProviderName: p.label, Prices: p.prices}, nil
+}
+
+func Test_service_GetAllLastPrices(t *testing.T) {
+	prices := []*CurrencyPrice{{Desc: "DAI/ARS", BidPrice: MustParseMoney("120"), AskPrice: MustParseMoney("125")}}
+
+	r := NewRegistry()
+	err := r.Register(
+		&fakeProvider{label: "fast", prices: prices},
+		&fakeProvider{label: "slow", delay: time.Second, prices: prices},
+		&fakeProvider{label: "broken", err: errors.New("upstream error")},
+	)
+	if err != nil {
+		t.Fatalf("registering providers: %v", err)
+	}
+
+	s := NewService(&options.ProvidersConfig{FetchTimeout: 50 * time.Millisecond}, r, zap.NewNop())
+
+	start := time.Now()
+	results := s.GetAllLastPrices(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetAllLastPrices() took %v, slow provider should not block the rest", elapsed)
+	}
+
+	tests := []struct {
+		name      string
+		wantErr   error
+		wantFound bool
+	}{
+		{name: "fast", wantFound: true},
+		{name: "slow", wantErr: ErrFetchTimeout},
+		{name: "broken"},
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("GetAllLastPrices() returned %d results, want %d", len(results), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := results[i]
+			if got.ProviderName != tt.name {
+				t.Errorf("ProviderName = %v, want %v", got.ProviderName, tt.name)
+			}
+			if tt.wantFound != (got.Err == nil && got.PriceList != nil) {
+				t.Errorf("result = %+v, want prices %v", got, tt.wantFound)
+			}
+			if tt.wantErr != nil && errors.Cause(got.Err) != tt.wantErr {
+				t.Errorf("Err = %v, want %v", got.Err, tt.wantErr)
+			}
+		})
+	}
+}
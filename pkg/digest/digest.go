@@ -0,0 +1,91 @@
+package digest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidFormat = errors.New("invalid subscription format")
+
+// Subscription delivers the prices of a provider to a chat every day at Hour:Minute in its timezone.
+type Subscription struct {
+	ChatID     int64
+	Provider   string
+	Hour       int
+	Minute     int
+	Timezone   string
+	CreatedAt  time.Time
+	LastSentAt time.Time
+}
+
+// Time returns the delivery time as HH:MM.
+func (s *Subscription) Time() string {
+	return fmt.Sprintf("%02d:%02d", s.Hour, s.Minute)
+}
+
+// LastSchedule returns the latest delivery time not after now.
+func (s *Subscription) LastSchedule(now time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "loading timezone %s", s.Timezone)
+	}
+
+	local := now.In(loc)
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), s.Hour, s.Minute, 0, 0, loc)
+	if scheduled.After(now) {
+		scheduled = scheduled.AddDate(0, 0, -1)
+	}
+	return scheduled, nil
+}
+
+// IsDue reports whether the digest must be sent at now, deliveries delayed more than maxDelay
+// (e.g. while the bot was down) are skipped until the next day.
+func (s *Subscription) IsDue(now time.Time, maxDelay time.Duration) (bool, error) {
+	scheduled, err := s.LastSchedule(now)
+	if err != nil {
+		return false, err
+	}
+	return s.LastSentAt.Before(scheduled) && now.Sub(scheduled) <= maxDelay, nil
+}
+
+// Parse parses the arguments of the subscribe command: <proveedor> <HH:MM>, the provider can contain spaces.
+func Parse(args string) (*Subscription, error) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return nil, ErrInvalidFormat
+	}
+
+	n := len(fields)
+	hour, minute, err := parseTime(fields[n-1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Subscription{
+		Provider: strings.Join(fields[:n-1], " "),
+		Hour:     hour,
+		Minute:   minute,
+	}, nil
+}
+
+func parseTime(s string) (int, int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, 0, errors.Wrapf(ErrInvalidFormat, "invalid time %s", s)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, errors.Wrapf(ErrInvalidFormat, "invalid hour %s", parts[0])
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || len(parts[1]) != 2 || minute < 0 || minute > 59 {
+		return 0, 0, errors.Wrapf(ErrInvalidFormat, "invalid minute %s", parts[1])
+	}
+
+	return hour, minute, nil
+}
@@ -0,0 +1,118 @@
+package digest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    *Subscription
+		wantErr error
+	}{
+		{
+			name: "provider and time",
+			args: "Dolar 09:30",
+			want: &Subscription{Provider: "Dolar", Hour: 9, Minute: 30},
+		},
+		{
+			name: "provider with spaces",
+			args: "Satoshi Tango 18:05",
+			want: &Subscription{Provider: "Satoshi Tango", Hour: 18, Minute: 5},
+		},
+		{
+			name:    "missing time",
+			args:    "Dolar",
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "invalid hour",
+			args:    "Dolar 24:00",
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "invalid minute",
+			args:    "Dolar 9:5",
+			wantErr: ErrInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscription_IsDue(t *testing.T) {
+	// 09:00 in Buenos Aires is 12:00 UTC
+	scheduled := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		lastSentAt time.Time
+		want       bool
+	}{
+		{
+			name:       "before the delivery time",
+			now:        scheduled.Add(-time.Minute),
+			lastSentAt: scheduled.Add(-24 * time.Hour),
+			want:       false,
+		},
+		{
+			name:       "at the delivery time",
+			now:        scheduled,
+			lastSentAt: scheduled.Add(-24 * time.Hour),
+			want:       true,
+		},
+		{
+			name:       "already delivered today",
+			now:        scheduled.Add(10 * time.Minute),
+			lastSentAt: scheduled.Add(time.Minute),
+			want:       false,
+		},
+		{
+			name:       "delivery late after a restart",
+			now:        scheduled.Add(30 * time.Minute),
+			lastSentAt: scheduled.Add(-24 * time.Hour),
+			want:       true,
+		},
+		{
+			name:       "too late to deliver",
+			now:        scheduled.Add(2 * time.Hour),
+			lastSentAt: scheduled.Add(-24 * time.Hour),
+			want:       false,
+		},
+		{
+			name:       "subscribed after today's delivery time",
+			now:        scheduled.Add(20 * time.Minute),
+			lastSentAt: scheduled.Add(10 * time.Minute),
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Subscription{Hour: 9, Minute: 0, Timezone: "America/Argentina/Buenos_Aires", LastSentAt: tt.lastSentAt}
+			got, err := s.IsDue(tt.now, time.Hour)
+			if err != nil {
+				t.Fatalf("IsDue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	tb "github.com/go-telegram-bot-api/telegram-bot-api"
+	"go.uber.org/zap"
+)
+
+type notifier interface {
+	Send(c tb.Chattable) (tb.Message, error)
+}
+
+type pricesFetcher interface {
+	GetLastPrices(ctx context.Context, providerName string) (*currency.CurrencyPriceList, error)
+}
+
+type templateEngine interface {
+	FormatPricesMessage(priceList *currency.CurrencyPriceList) (string, error)
+}
+
+// scheduler delivers the due digests. The last delivery is stored with each subscription so
+// a restart neither skips nor repeats a digest.
+type scheduler struct {
+	config          *options.DigestConfig
+	repository      repository
+	currencyService pricesFetcher
+	notifier        notifier
+	templateEngine  templateEngine
+	logger          *zap.Logger
+}
+
+func NewScheduler(c *options.DigestConfig, r repository, cs pricesFetcher, n notifier, t templateEngine, l *zap.Logger) *scheduler {
+	return &scheduler{
+		config:          c,
+		repository:      r,
+		currencyService: cs,
+		notifier:        n,
+		templateEngine:  t,
+		logger:          l,
+	}
+}
+
+// Run checks the subscriptions right away and then every check interval until the context is done.
+func (s *scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.check(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *scheduler) check(ctx context.Context, now time.Time) {
+	subs, err := s.repository.ListSubscriptions()
+	if err != nil {
+		s.logger.Error("listing subscriptions", zap.Error(err))
+		return
+	}
+
+	// format each provider once
+	messages := make(map[string]string)
+	for _, sub := range subs {
+		due, err := sub.IsDue(now, s.config.MaxDelay)
+		if err != nil {
+			s.logger.Error("checking subscription", zap.Int64("chatID", sub.ChatID), zap.String("provider", sub.Provider), zap.Error(err))
+			continue
+		}
+		if !due {
+			continue
+		}
+
+		text, found := messages[sub.Provider]
+		if !found {
+			text, err = s.formatDigest(ctx, sub.Provider)
+			if err != nil {
+				s.logger.Error("formatting digest", zap.String("provider", sub.Provider), zap.Error(err))
+				continue
+			}
+			messages[sub.Provider] = text
+		}
+
+		s.deliver(sub, text, now)
+	}
+}
+
+func (s *scheduler) formatDigest(ctx context.Context, providerName string) (string, error) {
+	priceList, err := s.currencyService.GetLastPrices(ctx, providerName)
+	if err != nil {
+		return "", err
+	}
+	return s.templateEngine.FormatPricesMessage(priceList)
+}
+
+func (s *scheduler) deliver(sub *Subscription, text string, now time.Time) {
+	msg := tb.NewMessage(sub.ChatID, text)
+	msg.ParseMode = tb.ModeHTML
+	if _, err := s.notifier.Send(msg); err != nil {
+		s.logger.Error("sending digest", zap.Int64("chatID", sub.ChatID), zap.String("provider", sub.Provider), zap.Error(err))
+		return
+	}
+
+	sub.LastSentAt = now
+	if err := s.repository.SaveSubscription(sub); err != nil {
+		s.logger.Error("saving digest delivery", zap.Int64("chatID", sub.ChatID), zap.String("provider", sub.Provider), zap.Error(err))
+	}
+}
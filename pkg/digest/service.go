@@ -0,0 +1,111 @@
+package digest
+
+import (
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNotFound = errors.New("subscription not found")
+
+type providerFinder interface {
+	ProviderByName(name string) (currency.ProviderInfo, bool)
+}
+
+type repository interface {
+	// SaveSubscription creates or replaces the subscription of the chat to the provider
+	SaveSubscription(s *Subscription) error
+	ListSubscriptions() ([]*Subscription, error)
+	ListChatSubscriptions(chatID int64) ([]*Subscription, error)
+	// DeleteSubscription fails with ErrNotFound when the chat is not subscribed to the provider
+	DeleteSubscription(chatID int64, provider string) error
+}
+
+type service struct {
+	config          *options.DigestConfig
+	repository      repository
+	currencyService providerFinder
+}
+
+func NewService(c *options.DigestConfig, r repository, cs providerFinder) *service {
+	return &service{config: c, repository: r, currencyService: cs}
+}
+
+// Subscribe parses the subscribe command arguments and stores the subscription, replacing the
+// previous delivery time if the chat was already subscribed to the provider. An empty timezone
+// means the configured default one.
+func (s *service) Subscribe(chatID int64, timezone string, args string) (*Subscription, error) {
+	sub, err := Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	p, found := s.currencyService.ProviderByName(sub.Provider)
+	if !found {
+		return nil, currency.ErrUnknownProvider
+	}
+
+	if timezone == "" {
+		timezone = s.config.DefaultTimezone
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, errors.Wrapf(err, "loading timezone %s", timezone)
+	}
+
+	now := time.Now()
+	sub.ChatID = chatID
+	sub.Provider = p.Label
+	sub.Timezone = timezone
+	sub.CreatedAt = now
+	// don't deliver right away when today's time already passed
+	sub.LastSentAt = now
+
+	if err := s.repository.SaveSubscription(sub); err != nil {
+		return nil, errors.Wrap(err, "storing subscription")
+	}
+
+	return sub, nil
+}
+
+func (s *service) List(chatID int64) ([]*Subscription, error) {
+	return s.repository.ListChatSubscriptions(chatID)
+}
+
+// Unsubscribe deletes the subscription to the provider, or every subscription of the chat when
+// providerName is empty, returning the deleted ones.
+func (s *service) Unsubscribe(chatID int64, providerName string) ([]*Subscription, error) {
+	subs, err := s.repository.ListChatSubscriptions(chatID)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing chat subscriptions")
+	}
+
+	if providerName != "" {
+		p, found := s.currencyService.ProviderByName(providerName)
+		if !found {
+			return nil, currency.ErrUnknownProvider
+		}
+
+		var matching []*Subscription
+		for _, sub := range subs {
+			if sub.Provider == p.Label {
+				matching = append(matching, sub)
+			}
+		}
+		subs = matching
+	}
+
+	if len(subs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	for _, sub := range subs {
+		if err := s.repository.DeleteSubscription(chatID, sub.Provider); err != nil {
+			return nil, errors.Wrapf(err, "deleting subscription to %s", sub.Provider)
+		}
+	}
+
+	return subs, nil
+}
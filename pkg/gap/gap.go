@@ -0,0 +1,19 @@
+package gap
+
+import (
+	"coinbani/pkg/currency"
+)
+
+// quote identifies a pair quoted by a provider.
+type quote struct {
+	provider string
+	pair     string
+}
+
+// Gap is a gap between two dollars with its change since a day ago.
+type Gap struct {
+	*currency.Gap
+	// Change is the variation of the gap in percentage points, only set when HasChange
+	Change    currency.Money
+	HasChange bool
+}
@@ -0,0 +1,191 @@
+package gap
+
+import (
+	"context"
+	"time"
+
+	"coinbani/pkg/currency"
+	"coinbani/pkg/store"
+
+	"github.com/pkg/errors"
+)
+
+// snapshotWindow is how far back from a day ago a recorded price is still used for the daily change
+const snapshotWindow = time.Hour
+
+var ErrNoGaps = errors.New("no gap could be computed")
+
+type pricesFetcher interface {
+	GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices
+}
+
+type snapshotStore interface {
+	ListPriceSnapshots(provider string, pair string, from time.Time, to time.Time) ([]*store.PriceSnapshot, error)
+}
+
+// Report holds the current gaps, the dollar provider ones first.
+type Report struct {
+	Gaps []*Gap
+	// Unavailable are the gaps missing a quote
+	Unavailable []string
+}
+
+type service struct {
+	store           snapshotStore
+	currencyService pricesFetcher
+	providerName    string
+}
+
+// NewService computes the gaps of the dollar provider with the given name.
+func NewService(s snapshotStore, cs pricesFetcher, providerName string) *service {
+	return &service{store: s, currencyService: cs, providerName: providerName}
+}
+
+// Gaps returns the gaps computed by the currency layer on the dollar provider, plus the crypto
+// dollar one, with their change since a day ago from the recorded prices.
+func (s *service) Gaps(ctx context.Context) (*Report, error) {
+	report := &Report{}
+	dayAgo := time.Now().Add(-24 * time.Hour)
+
+	// a failed provider only makes its gaps unavailable
+	var dollars *currency.CurrencyPriceList
+	var cryptoLists []*currency.CurrencyPriceList
+	for _, r := range s.currencyService.GetAllLastPrices(ctx) {
+		if r.Err != nil || r.PriceList == nil {
+			continue
+		}
+		if r.ProviderName == s.providerName {
+			dollars = r.PriceList
+		} else {
+			cryptoLists = append(cryptoLists, r.PriceList)
+		}
+	}
+
+	for _, d := range currency.DollarGaps {
+		g := findGap(dollars, d.Name)
+		if g == nil {
+			report.Unavailable = append(report.Unavailable, d.Name)
+			continue
+		}
+
+		previous, err := s.recordedGap(d, dayAgo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting %s previous gap", d.Name)
+		}
+		report.Gaps = append(report.Gaps, withChange(g, previous))
+	}
+
+	crypto, err := s.cryptoGap(dollars, cryptoLists, dayAgo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %s gap", currency.CryptoGapName)
+	}
+	if crypto != nil {
+		report.Gaps = append(report.Gaps, crypto)
+	} else {
+		report.Unavailable = append(report.Unavailable, currency.CryptoGapName)
+	}
+
+	if len(report.Gaps) == 0 {
+		return nil, ErrNoGaps
+	}
+	return report, nil
+}
+
+// cryptoGap returns the crypto dollar gap computed by the currency layer, the gap a day ago is
+// computed from the recorded quotes of the same providers and assets.
+func (s *service) cryptoGap(dollars *currency.CurrencyPriceList, lists []*currency.CurrencyPriceList, at time.Time) (*Gap, error) {
+	current := currency.ComputeCryptoGap(dollars, lists)
+	if current == nil {
+		return nil, nil
+	}
+
+	mep, err := s.recordedPrice(quote{provider: s.providerName, pair: currency.CryptoGapBase}, at)
+	if err != nil || mep == nil {
+		return withChange(current, nil), err
+	}
+	recordedDollars := &currency.CurrencyPriceList{ProviderName: s.providerName, Prices: []*currency.CurrencyPrice{mep}}
+
+	var recordedLists []*currency.CurrencyPriceList
+	for _, l := range lists {
+		cd, found := l.CryptoDollar()
+		if !found {
+			continue
+		}
+
+		recorded, err := s.recordedCryptoDollarList(l.ProviderName, cd.Asset, at)
+		if err != nil {
+			return nil, err
+		}
+		if recorded != nil {
+			recordedLists = append(recordedLists, recorded)
+		}
+	}
+
+	return withChange(current, currency.ComputeCryptoGap(recordedDollars, recordedLists)), nil
+}
+
+// recordedGap returns the gap from the dollar provider prices recorded before at, nil when not recorded.
+func (s *service) recordedGap(d *currency.GapDefinition, at time.Time) (*currency.Gap, error) {
+	price, err := s.recordedPrice(quote{provider: s.providerName, pair: d.Pair}, at)
+	if err != nil || price == nil {
+		return nil, err
+	}
+
+	base, err := s.recordedPrice(quote{provider: s.providerName, pair: d.Base}, at)
+	if err != nil || base == nil {
+		return nil, err
+	}
+
+	return currency.NewGap(d.Name, price.AskPrice, base.AskPrice), nil
+}
+
+// recordedCryptoDollarList returns the asset prices of the provider recorded before at, nil when
+// not recorded.
+func (s *service) recordedCryptoDollarList(providerName string, asset string, at time.Time) (*currency.CurrencyPriceList, error) {
+	l := &currency.CurrencyPriceList{ProviderName: providerName}
+	for _, pair := range []string{asset + "/ARS", asset + "/USD"} {
+		p, err := s.recordedPrice(quote{provider: providerName, pair: pair}, at)
+		if err != nil || p == nil {
+			return nil, err
+		}
+		l.Prices = append(l.Prices, p)
+	}
+	return l, nil
+}
+
+// recordedPrice returns the last price recorded before at, within the snapshot window.
+func (s *service) recordedPrice(q quote, at time.Time) (*currency.CurrencyPrice, error) {
+	snapshots, err := s.store.ListPriceSnapshots(q.provider, q.pair, at.Add(-snapshotWindow), at)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].AskPrice.IsPositive() {
+			return &currency.CurrencyPrice{Desc: q.pair, BidPrice: snapshots[i].BidPrice, AskPrice: snapshots[i].AskPrice}, nil
+		}
+	}
+	return nil, nil
+}
+
+// withChange sets the change of the gap since the previous one, when there is one.
+func withChange(current *currency.Gap, previous *currency.Gap) *Gap {
+	g := &Gap{Gap: current}
+	if previous != nil {
+		g.Change = current.Percent.Sub(previous.Percent)
+		g.HasChange = true
+	}
+	return g
+}
+
+func findGap(l *currency.CurrencyPriceList, name string) *currency.Gap {
+	if l == nil {
+		return nil
+	}
+	for _, g := range l.Gaps {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
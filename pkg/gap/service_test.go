@@ -0,0 +1,131 @@
+package gap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"coinbani/pkg/currency"
+	"coinbani/pkg/store"
+
+	"github.com/pkg/errors"
+)
+
+type fakePricesFetcher []*currency.ProviderPrices
+
+func (f fakePricesFetcher) GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices {
+	return f
+}
+
+func prices(providerName string, asks map[string]string) *currency.ProviderPrices {
+	l := &currency.CurrencyPriceList{ProviderName: providerName}
+	for pair, ask := range asks {
+		l.Prices = append(l.Prices, &currency.CurrencyPrice{Desc: pair, BidPrice: currency.MustParseMoney(ask), AskPrice: currency.MustParseMoney(ask)})
+	}
+	return &currency.ProviderPrices{ProviderName: providerName, PriceList: l}
+}
+
+func snapshot(providerName string, pair string, ask string, at time.Time) *store.PriceSnapshot {
+	price := currency.MustParseMoney(ask)
+	return &store.PriceSnapshot{Provider: providerName, Pair: pair, BidPrice: price, AskPrice: price, At: at}
+}
+
+func TestService_Gaps(t *testing.T) {
+	dayAgo := time.Now().Add(-24*time.Hour - 10*time.Minute)
+	s := store.NewInMemoryStore()
+	s.AddPriceSnapshots([]*store.PriceSnapshot{
+		snapshot("Dolar", "Blue", "180", dayAgo),
+		snapshot("Dolar", "Oficial", "100", dayAgo),
+		snapshot("Dolar", "MEP", "160", dayAgo),
+		// too old for the daily change
+		snapshot("Dolar", "CCL", "150", dayAgo.Add(-2*time.Hour)),
+		snapshot("Buenbit", "DAI/ARS", "168", dayAgo),
+		snapshot("Buenbit", "DAI/USD", "1", dayAgo),
+		snapshot("Satoshi Tango", "USDT/ARS", "176", dayAgo),
+		snapshot("Satoshi Tango", "USDT/USD", "1", dayAgo),
+	})
+
+	dollar := prices("Dolar", map[string]string{"Oficial": "100", "Blue": "200", "MEP": "190", "CCL": "200"})
+	// the gaps computed by the currency service
+	dollar.PriceList.Gaps = []*currency.Gap{
+		currency.NewGap("Blue vs Oficial", currency.MustParseMoney("200"), currency.MustParseMoney("100")),
+		currency.NewGap("MEP vs CCL", currency.MustParseMoney("190"), currency.MustParseMoney("200")),
+	}
+	// crypto dollars of 209, 200 and 228, the last one without recorded prices
+	bb := prices("Buenbit", map[string]string{"DAI/ARS": "209", "DAI/USD": "1"})
+	satoshiT := prices("Satoshi Tango", map[string]string{"USDT/ARS": "200", "USDT/USD": "1"})
+	ripio := prices("Ripio", map[string]string{"USDC/ARS": "228", "USDC/USD": "1"})
+	down := &currency.ProviderPrices{ProviderName: "Binance P2P", Err: errors.New("upstream error")}
+
+	type wantGap struct {
+		name      string
+		percent   string
+		change    string
+		hasChange bool
+	}
+	tests := []struct {
+		name            string
+		fetcher         fakePricesFetcher
+		want            []wantGap
+		wantUnavailable []string
+		wantErr         error
+	}{
+		{
+			name:    "every gap",
+			fetcher: fakePricesFetcher{dollar, bb, satoshiT, ripio, down},
+			want: []wantGap{
+				{name: "Blue vs Oficial", percent: "100", change: "20", hasChange: true},
+				{name: "MEP vs CCL", percent: "-5"},
+				// median of 209 against 190, a day ago median of 172 against 160
+				{name: "Cripto vs MEP", percent: "10", change: "2.5", hasChange: true},
+			},
+		},
+		{
+			name:    "crypto dollar of a single provider",
+			fetcher: fakePricesFetcher{dollar, ripio},
+			want: []wantGap{
+				{name: "Blue vs Oficial", percent: "100", change: "20", hasChange: true},
+				{name: "MEP vs CCL", percent: "-5"},
+				{name: "Cripto vs MEP", percent: "20"},
+			},
+		},
+		{
+			name:            "crypto dollar providers down",
+			fetcher:         fakePricesFetcher{dollar, down},
+			want:            []wantGap{{name: "Blue vs Oficial", percent: "100", change: "20", hasChange: true}, {name: "MEP vs CCL", percent: "-5"}},
+			wantUnavailable: []string{"Cripto vs MEP"},
+		},
+		{
+			name:    "no quotes",
+			fetcher: fakePricesFetcher{},
+			wantErr: ErrNoGaps,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(s, tt.fetcher, "Dolar").Gaps(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Gaps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(got.Gaps) != len(tt.want) {
+				t.Fatalf("Gaps() returned %d gaps, want %d", len(got.Gaps), len(tt.want))
+			}
+			for i, g := range got.Gaps {
+				w := tt.want[i]
+				if g.Name != w.name || !g.Percent.Equal(currency.MustParseMoney(w.percent)) || g.HasChange != w.hasChange {
+					t.Errorf("Gaps() = %+v, want %+v", g, w)
+				}
+				if w.hasChange && !g.Change.Equal(currency.MustParseMoney(w.change)) {
+					t.Errorf("Gaps() %s change = %v, want %v", g.Name, g.Change, w.change)
+				}
+			}
+			if len(got.Unavailable) != len(tt.wantUnavailable) {
+				t.Errorf("Gaps() unavailable = %v, want %v", got.Unavailable, tt.wantUnavailable)
+			}
+		})
+	}
+}
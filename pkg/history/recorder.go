@@ -0,0 +1,125 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+	"coinbani/pkg/store"
+
+	"go.uber.org/zap"
+)
+
+// minRetention keeps the snapshots of the longest period
+const minRetention = 30 * 24 * time.Hour
+
+// pruneInterval is how often the snapshots older than the retention are deleted
+const pruneInterval = time.Hour
+
+type snapshotStore interface {
+	AddPriceSnapshots(snapshots []*store.PriceSnapshot) error
+	ListPriceSnapshots(provider string, pair string, from time.Time, to time.Time) ([]*store.PriceSnapshot, error)
+	DeletePriceSnapshotsBefore(before time.Time) (int, error)
+}
+
+type pricesFetcher interface {
+	GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices
+}
+
+// recorder periodically samples every provider and stores its quotes, deleting the ones older
+// than the retention.
+type recorder struct {
+	config          *options.HistoryConfig
+	store           snapshotStore
+	currencyService pricesFetcher
+	logger          *zap.Logger
+	// lastFetchedAt avoids recording the same cached quotes twice
+	lastFetchedAt map[string]time.Time
+	lastPrunedAt  time.Time
+}
+
+func NewRecorder(c *options.HistoryConfig, s snapshotStore, cs pricesFetcher, l *zap.Logger) *recorder {
+	return &recorder{
+		config:          c,
+		store:           s,
+		currencyService: cs,
+		logger:          l,
+		lastFetchedAt:   make(map[string]time.Time),
+	}
+}
+
+// Run records the prices every record interval until the context is done.
+func (r *recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.RecordInterval)
+	defer ticker.Stop()
+
+	r.record(ctx)
+	r.prune(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.record(ctx)
+			r.prune(time.Now())
+		}
+	}
+}
+
+// prune deletes the snapshots older than the retention, at most once every prune interval.
+func (r *recorder) prune(now time.Time) {
+	if now.Sub(r.lastPrunedAt) < pruneInterval {
+		return
+	}
+	r.lastPrunedAt = now
+
+	retention := r.config.Retention
+	if retention < minRetention {
+		retention = minRetention
+	}
+
+	deleted, err := r.store.DeletePriceSnapshotsBefore(now.Add(-retention))
+	if err != nil {
+		r.logger.Error("deleting old price snapshots", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		r.logger.Debug("deleted old price snapshots", zap.Int("count", deleted))
+	}
+}
+
+func (r *recorder) record(ctx context.Context) {
+	var snapshots []*store.PriceSnapshot
+	for _, result := range r.currencyService.GetAllLastPrices(ctx) {
+		if result.Err != nil {
+			continue
+		}
+
+		priceList := result.PriceList
+		if priceList.IsStale() || !priceList.FetchedAt.After(r.lastFetchedAt[result.ProviderName]) {
+			continue
+		}
+		r.lastFetchedAt[result.ProviderName] = priceList.FetchedAt
+
+		for _, p := range priceList.Prices {
+			snapshots = append(snapshots, &store.PriceSnapshot{
+				Provider: result.ProviderName,
+				Pair:     p.Desc,
+				BidPrice: p.BidPrice,
+				AskPrice: p.AskPrice,
+				At:       priceList.FetchedAt,
+			})
+		}
+	}
+
+	if len(snapshots) == 0 {
+		return
+	}
+
+	if err := r.store.AddPriceSnapshots(snapshots); err != nil {
+		r.logger.Error("storing price snapshots", zap.Error(err))
+		return
+	}
+	r.logger.Debug("recorded price snapshots", zap.Int("count", len(snapshots)))
+}
@@ -0,0 +1,45 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+	"coinbani/pkg/store"
+
+	"go.uber.org/zap"
+)
+
+func Test_recorder_prune(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		wantKept  int
+	}{
+		{name: "retention", retention: 40 * day, wantKept: 3},
+		{name: "retention below the minimum", retention: day, wantKept: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := store.NewInMemoryStore()
+			s.AddPriceSnapshots([]*store.PriceSnapshot{
+				{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("100"), At: now.Add(-50 * day)},
+				{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("110"), At: now.Add(-35 * day)},
+				{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("120"), At: now.Add(-20 * day)},
+				{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("130"), At: now.Add(-time.Hour)},
+			})
+
+			r := NewRecorder(&options.HistoryConfig{Retention: tt.retention}, s, nil, zap.NewNop())
+			r.prune(now)
+
+			got, _ := s.ListPriceSnapshots("Dolar", "Blue", now.Add(-100*day), now)
+			if len(got) != tt.wantKept {
+				t.Errorf("prune() kept %d snapshots, want %d", len(got), tt.wantKept)
+			}
+		})
+	}
+}
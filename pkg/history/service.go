@@ -0,0 +1,167 @@
+package history
+
+import (
+	"strings"
+	"time"
+
+	"coinbani/pkg/currency"
+	"coinbani/pkg/store"
+
+	"github.com/pkg/errors"
+)
+
+const DefaultPeriod = "24h"
+
+var (
+	ErrUnknownPair   = errors.New("unknown pair")
+	ErrInvalidPeriod = errors.New("invalid period")
+
+	periods = map[string]time.Duration{
+		"24h": 24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+	}
+)
+
+// Stats summarizes the ask price of a pair quoted by a provider over a period.
+type Stats struct {
+	Provider      string
+	Pair          string
+	Period        string
+	Open          currency.Money
+	Close         currency.Money
+	Min           currency.Money
+	Max           currency.Money
+	Change        currency.Money
+	ChangePercent currency.Money
+	Samples       int
+	From          time.Time
+	To            time.Time
+}
+
+// Series are the recorded prices of a pair quoted by a provider, oldest first.
+type Series struct {
+	Provider  string
+	Pair      string
+	Snapshots []*store.PriceSnapshot
+}
+
+type providerLister interface {
+	Providers() []currency.ProviderInfo
+}
+
+type service struct {
+	store           snapshotStore
+	currencyService providerLister
+}
+
+func NewService(s snapshotStore, cs providerLister) *service {
+	return &service{store: s, currencyService: cs}
+}
+
+// ParsePeriod returns the duration of a period name, the default period when empty.
+func ParsePeriod(period string) (string, time.Duration, error) {
+	if period == "" {
+		period = DefaultPeriod
+	}
+
+	period = strings.ToLower(period)
+	d, found := periods[period]
+	if !found {
+		return "", 0, ErrInvalidPeriod
+	}
+	return period, d, nil
+}
+
+// GetStats returns the stats of the pair for every provider quoting it, skipping
+// those without recorded prices in the period.
+func (s *service) GetStats(pair string, period string) ([]*Stats, error) {
+	series, err := s.GetSeries(pair, period)
+	if err != nil {
+		return nil, err
+	}
+
+	period, _, _ = ParsePeriod(period)
+	stats := make([]*Stats, 0, len(series))
+	for _, ps := range series {
+		st := computeStats(ps.Snapshots)
+		st.Period = period
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+// GetSeries returns the recorded prices of the pair for every provider quoting it,
+// skipping those without recorded prices in the period.
+func (s *service) GetSeries(pair string, period string) ([]*Series, error) {
+	_, d, err := ParsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	from := to.Add(-d)
+
+	found := false
+	var series []*Series
+	for _, p := range s.currencyService.Providers() {
+		providerPair, ok := findPair(p.Pairs, pair)
+		if !ok {
+			continue
+		}
+		found = true
+
+		snapshots, err := s.store.ListPriceSnapshots(p.Label, providerPair, from, to)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing %s snapshots", p.Label)
+		}
+		if len(snapshots) == 0 {
+			continue
+		}
+
+		series = append(series, &Series{Provider: p.Label, Pair: providerPair, Snapshots: snapshots})
+	}
+
+	if !found {
+		return nil, ErrUnknownPair
+	}
+	return series, nil
+}
+
+func findPair(pairs []string, pair string) (string, bool) {
+	for _, p := range pairs {
+		if strings.EqualFold(p, pair) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// computeStats expects at least one snapshot sorted by time.
+func computeStats(snapshots []*store.PriceSnapshot) *Stats {
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	st := &Stats{
+		Provider: first.Provider,
+		Pair:     first.Pair,
+		Open:     first.AskPrice,
+		Close:    last.AskPrice,
+		Min:      first.AskPrice,
+		Max:      first.AskPrice,
+		Samples:  len(snapshots),
+		From:     first.At,
+		To:       last.At,
+	}
+
+	for _, s := range snapshots {
+		if s.AskPrice.LessThan(st.Min) {
+			st.Min = s.AskPrice
+		}
+		if s.AskPrice.GreaterThan(st.Max) {
+			st.Max = s.AskPrice
+		}
+	}
+
+	st.Change = st.Close.Sub(st.Open)
+	st.ChangePercent = currency.PercentChange(st.Open, st.Close)
+	return st
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"coinbani/pkg/currency"
+	"coinbani/pkg/store"
+)
+
+type fakeProviderLister []currency.ProviderInfo
+
+func (l fakeProviderLister) Providers() []currency.ProviderInfo {
+	return l
+}
+
+func Test_service_GetStats(t *testing.T) {
+	now := time.Now()
+	s := store.NewInMemoryStore()
+	s.AddPriceSnapshots([]*store.PriceSnapshot{
+		{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("128"), At: now.Add(-48 * time.Hour)},
+		{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("80"), At: now.Add(-20 * time.Hour)},
+		{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("120"), At: now.Add(-10 * time.Hour)},
+		{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("136"), At: now.Add(-5 * time.Hour)},
+		{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MustParseMoney("160"), At: now.Add(-time.Hour)},
+	})
+	providers := fakeProviderLister{
+		{Label: "Dolar", Pairs: []string{"Oficial", "Blue"}},
+		{Label: "Buenbit", Pairs: []string{"DAI/ARS"}},
+	}
+
+	tests := []struct {
+		name    string
+		pair    string
+		period  string
+		want    []*Stats
+		wantErr error
+	}{
+		{
+			name:   "last 24 hours",
+			pair:   "blue",
+			period: "",
+			want: []*Stats{{
+				Provider: "Dolar", Pair: "Blue", Period: "24h",
+				Open: currency.MustParseMoney("80"), Close: currency.MustParseMoney("160"), Min: currency.MustParseMoney("80"), Max: currency.MustParseMoney("160"), Change: currency.MustParseMoney("80"), ChangePercent: currency.MustParseMoney("100"), Samples: 4,
+			}},
+		},
+		{
+			name:   "last 7 days",
+			pair:   "Blue",
+			period: "7d",
+			want: []*Stats{{
+				Provider: "Dolar", Pair: "Blue", Period: "7d",
+				Open: currency.MustParseMoney("128"), Close: currency.MustParseMoney("160"), Min: currency.MustParseMoney("80"), Max: currency.MustParseMoney("160"), Change: currency.MustParseMoney("32"), ChangePercent: currency.MustParseMoney("25"), Samples: 5,
+			}},
+		},
+		{
+			name:   "known pair without data",
+			pair:   "DAI/ARS",
+			period: "7d",
+		},
+		{
+			name:    "unknown pair",
+			pair:    "DOGE/ARS",
+			wantErr: ErrUnknownPair,
+		},
+		{
+			name:    "invalid period",
+			pair:    "Blue",
+			period:  "1y",
+			wantErr: ErrInvalidPeriod,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewService(s, providers).GetStats(tt.pair, tt.period)
+			if err != tt.wantErr {
+				t.Fatalf("GetStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetStats() = %v, want %v", got, tt.want)
+			}
+			for i, st := range got {
+				if !statsEqual(st, tt.want[i]) {
+					t.Errorf("GetStats() = %+v, want %+v", st, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// statsEqual compares the amounts by value, ignoring the time bounds that depend on the current time.
+func statsEqual(a *Stats, b *Stats) bool {
+	return a.Provider == b.Provider && a.Pair == b.Pair && a.Period == b.Period && a.Samples == b.Samples &&
+		a.Open.Equal(b.Open) && a.Close.Equal(b.Close) && a.Min.Equal(b.Min) && a.Max.Equal(b.Max) &&
+		a.Change.Equal(b.Change) && a.ChangePercent.Equal(b.ChangePercent)
+}
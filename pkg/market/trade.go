@@ -0,0 +1,79 @@
+package market
+
+import (
+	"strings"
+
+	"coinbani/pkg/compare"
+	"coinbani/pkg/currency"
+)
+
+// usdMarkets are the dollar provider quotes that can be traded as USD/ARS.
+var usdMarkets = map[string]bool{
+	"BLUE": true,
+	"MEP":  true,
+	"CCL":  true,
+}
+
+// IsUSDMarket reports whether name is a dollar market such as Blue or MEP.
+func IsUSDMarket(name string) bool {
+	return usdMarkets[compare.NormalizePair(name)]
+}
+
+// Trade converts From into To at Rate units of To per unit of From, before fees.
+type Trade struct {
+	Provider string
+	Market   string
+	From     string
+	To       string
+	Rate     currency.Money
+}
+
+// Where returns the provider of the trade, followed by the market for the dollar quotes, e.g. "Dolar Blue".
+func (t *Trade) Where() string {
+	if IsUSDMarket(t.Market) {
+		return t.Provider + " " + t.Market
+	}
+	return t.Provider
+}
+
+// Route returns the trades as "ARS → DAI (Buenbit) → USD (Buenbit)".
+func Route(trades []*Trade) string {
+	if len(trades) == 0 {
+		return ""
+	}
+
+	steps := []string{trades[0].From}
+	for _, t := range trades {
+		steps = append(steps, t.To+" ("+t.Where()+")")
+	}
+	return strings.Join(steps, " → ")
+}
+
+// BuildTrades returns both sides of every quote: buying the base currency at the ask price
+// and selling it at the bid price. Buenbit's ARS/USD is derived from its DAI books, so
+// it's traded as a single conversion.
+func BuildTrades(priceLists []*currency.CurrencyPriceList) []*Trade {
+	var trades []*Trade
+	for _, priceList := range priceLists {
+		for _, price := range priceList.Prices {
+			pair := compare.NormalizePair(price.Desc)
+			if usdMarkets[pair] {
+				pair = "USD/ARS"
+			}
+
+			parts := strings.Split(pair, "/")
+			if len(parts) != 2 {
+				continue
+			}
+			base, quote := parts[0], parts[1]
+
+			if price.AskPrice.IsPositive() {
+				trades = append(trades, &Trade{Provider: priceList.ProviderName, Market: price.Desc, From: quote, To: base, Rate: currency.MoneyFromInt(1).Div(price.AskPrice)})
+			}
+			if price.BidPrice.IsPositive() {
+				trades = append(trades, &Trade{Provider: priceList.ProviderName, Market: price.Desc, From: base, To: quote, Rate: price.BidPrice})
+			}
+		}
+	}
+	return trades
+}
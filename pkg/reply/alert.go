@@ -0,0 +1,95 @@
+package reply
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"coinbani/pkg/alert"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	alertUsageMsg = `Uso: /alerta &lt;par&gt; &lt;proveedor&gt; &gt;|&lt; &lt;valor&gt;[%]
+Se compara contra el precio de venta, con % contra la variación desde que se creó la alerta.
+
+Ejemplos:
+/alerta Blue Dolar &gt; 150
+/alerta DAI/ARS Satoshi Tango &lt; -5%`
+	deleteAlertUsageMsg = "Uso: /borrar_alerta &lt;número&gt;, podés ver tus alertas con /alertas"
+)
+
+func (h *handler) handleCreateAlertCommand(ctx context.Context, chatID int64, args string) string {
+	h.logger.Info("handle create alert command", zap.Int64("chatID", chatID), zap.String("args", args))
+
+	a, err := h.alertService.Create(ctx, chatID, args)
+	switch {
+	case err == nil:
+		return fmt.Sprintf("Alerta #%d creada: %s en %s %s", a.ID, a.Pair, a.Provider, escapeHTML(a.Condition()))
+	case errors.Is(err, alert.ErrInvalidFormat):
+		return alertUsageMsg
+	case errors.Is(err, currency.ErrUnknownProvider):
+		return "Proveedor desconocido, las opciones son: " + h.providerNames()
+	case errors.Is(err, alert.ErrUnknownPair):
+		return "El proveedor no cotiza ese par, revisa las opciones con /cotizaciones"
+	case errors.Is(err, alert.ErrTooManyAlerts):
+		return "Alcanzaste el máximo de alertas, borra alguna con /borrar_alerta"
+	default:
+		h.logger.Error("creating alert", zap.Error(err))
+		return fetchErrorMessage(err)
+	}
+}
+
+func (h *handler) handleListAlertsCommand(chatID int64) string {
+	h.logger.Info("handle list alerts command", zap.Int64("chatID", chatID))
+
+	alerts, err := h.alertService.List(chatID)
+	if err != nil {
+		h.logger.Error("listing alerts", zap.Error(err))
+		return errorMsg
+	}
+
+	message, err := h.templateEngine.FormatAlertsMessage(alerts)
+	if err != nil {
+		h.logger.Error("formatting alerts template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
+
+func (h *handler) handleDeleteAlertCommand(chatID int64, args string) string {
+	h.logger.Info("handle delete alert command", zap.Int64("chatID", chatID), zap.String("args", args))
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(args), "#"), 10, 64)
+	if err != nil {
+		return deleteAlertUsageMsg
+	}
+
+	err = h.alertService.Delete(chatID, id)
+	switch {
+	case err == nil:
+		return fmt.Sprintf("Alerta #%d borrada", id)
+	case errors.Is(err, alert.ErrNotFound):
+		return fmt.Sprintf("No tienes una alerta #%d", id)
+	default:
+		h.logger.Error("deleting alert", zap.Error(err))
+		return errorMsg
+	}
+}
+
+func (h *handler) providerNames() string {
+	var names []string
+	for _, p := range h.currencyService.Providers() {
+		names = append(names, p.Label)
+	}
+	return strings.Join(names, ", ")
+}
+
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,55 @@
+package reply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"coinbani/pkg/currency"
+
+	"go.uber.org/zap"
+)
+
+const arbitrageUsageMsg = `Uso: /arbitraje para ver las oportunidades actuales
+/arbitraje alerta &lt;porcentaje&gt; para recibir un aviso cuando la ganancia lo supere
+/arbitraje alerta off para desactivar el aviso`
+
+func (h *handler) handleArbitrageCommand(ctx context.Context, chatID int64, args string) string {
+	h.logger.Info("handle arbitrage command", zap.Int64("chatID", chatID), zap.String("args", args))
+
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) == 0:
+		message, err := h.templateEngine.FormatArbitrageMessage(h.arbitrageService.Analyze(ctx))
+		if err != nil {
+			h.logger.Error("formatting arbitrage template", zap.Error(err))
+			return errorMsg
+		}
+		return message
+	case len(fields) == 2 && strings.EqualFold(fields[0], "alerta"):
+		return h.setArbitrageAlert(chatID, fields[1])
+	default:
+		return arbitrageUsageMsg
+	}
+}
+
+func (h *handler) setArbitrageAlert(chatID int64, rawThreshold string) string {
+	var threshold currency.Money
+	if !strings.EqualFold(rawThreshold, "off") {
+		v, err := currency.ParseMoney(strings.Replace(strings.TrimSuffix(rawThreshold, "%"), ",", ".", 1))
+		if err != nil || !v.IsPositive() {
+			return arbitrageUsageMsg
+		}
+		threshold = v
+	}
+
+	if err := h.arbitrageService.SetAlertThreshold(chatID, threshold); err != nil {
+		h.logger.Error("setting arbitrage alert", zap.Error(err))
+		return errorMsg
+	}
+
+	if threshold.IsZero() {
+		return "Alerta de arbitraje desactivada"
+	}
+	return fmt.Sprintf("Te avisaremos cuando haya una oportunidad de arbitraje de más de %s%%", threshold.StringFixed(2))
+}
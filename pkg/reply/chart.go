@@ -0,0 +1,87 @@
+package reply
+
+import (
+	"fmt"
+	"strings"
+
+	"coinbani/pkg/chart"
+	"coinbani/pkg/history"
+
+	tb "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const chartUsageMsg = "Uso: /grafico &lt;par&gt; [24h|7d|30d], por ejemplo /grafico Blue 7d"
+
+// handleChartCommand sends the chart as a photo, it returns a text reply only when the chart can't be sent.
+func (h *handler) handleChartCommand(chatID int64, args string) string {
+	h.logger.Info("handle chart command", zap.String("args", args))
+
+	pair, period, ok := parsePairAndPeriod(args)
+	if !ok {
+		return chartUsageMsg
+	}
+
+	series, err := h.historyService.GetSeries(pair, period)
+	switch {
+	case errors.Is(err, history.ErrInvalidPeriod):
+		return chartUsageMsg
+	case errors.Is(err, history.ErrUnknownPair):
+		return "Ningún proveedor cotiza ese par, revisa las opciones con /cotizaciones"
+	case err != nil:
+		h.logger.Error("getting price history", zap.Error(err))
+		return errorMsg
+	}
+
+	chartSeries := make([]*chart.Series, 0, len(series))
+	for _, s := range series {
+		cs := &chart.Series{Name: s.Provider}
+		for _, snapshot := range s.Snapshots {
+			cs.Points = append(cs.Points, chart.Point{At: snapshot.At, Value: snapshot.AskPrice.Float64()})
+		}
+		chartSeries = append(chartSeries, cs)
+	}
+
+	image, bounds, err := h.chartRenderer.RenderPNG(chartSeries)
+	if err != nil {
+		if errors.Is(err, chart.ErrNoData) {
+			return "Todavía no hay datos suficientes para graficar este período"
+		}
+		h.logger.Error("rendering chart", zap.Error(err))
+		return errorMsg
+	}
+
+	photo := tb.NewPhotoUpload(chatID, tb.FileBytes{Name: "grafico.png", Bytes: image})
+	photo.Caption = chartCaption(series[0].Pair, period, chartSeries, bounds)
+	if _, err := h.bot.Send(photo); err != nil {
+		h.logger.Error("sending chart", zap.Int64("chatID", chatID), zap.Error(err))
+		return errorMsg
+	}
+
+	return ""
+}
+
+func chartCaption(pair string, period string, series []*chart.Series, bounds *chart.Bounds) string {
+	lines := []string{fmt.Sprintf("%s %s (precio de venta)", pair, period)}
+	for i, s := range series {
+		lines = append(lines, fmt.Sprintf("%s: %s", chart.Palette[i%len(chart.Palette)].Name, s.Name))
+	}
+	lines = append(lines, fmt.Sprintf("Mínimo %.2f, máximo %.2f", bounds.MinValue, bounds.MaxValue))
+
+	return strings.Join(lines, "\n")
+}
+
+// parsePairAndPeriod parses "<par> [periodo]" arguments, the period defaults to history.DefaultPeriod.
+func parsePairAndPeriod(args string) (string, string, bool) {
+	fields := strings.Fields(args)
+	if len(fields) < 1 || len(fields) > 2 {
+		return "", "", false
+	}
+
+	period := history.DefaultPeriod
+	if len(fields) == 2 {
+		period = strings.ToLower(fields[1])
+	}
+	return fields[0], period, true
+}
@@ -0,0 +1,45 @@
+package reply
+
+import (
+	"context"
+	"strings"
+
+	"coinbani/pkg/compare"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const compareUsageMsg = "Uso: /mejor &lt;par&gt; comprar|vender, por ejemplo /mejor DAI/ARS comprar"
+
+func (h *handler) handleCompareCommand(ctx context.Context, args string) string {
+	h.logger.Info("handle compare command", zap.String("args", args))
+
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return compareUsageMsg
+	}
+
+	n := len(fields)
+	side, err := compare.ParseSide(fields[n-1])
+	if err != nil {
+		return compareUsageMsg
+	}
+
+	comparison, err := h.compareService.Compare(ctx, strings.Join(fields[:n-1], " "), side)
+	switch {
+	case errors.Is(err, compare.ErrNoQuotes):
+		return "Ningún proveedor cotiza ese par en este momento, revisa las opciones con /cotizaciones"
+	case err != nil:
+		h.logger.Error("comparing prices", zap.Error(err))
+		return errorMsg
+	}
+
+	message, err := h.templateEngine.FormatComparisonMessage(comparison)
+	if err != nil {
+		h.logger.Error("formatting comparison template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
@@ -0,0 +1,43 @@
+package reply
+
+import (
+	"context"
+
+	"coinbani/pkg/convert"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const convertUsageMsg = `Uso: /convertir &lt;monto&gt; &lt;desde&gt; &lt;hacia&gt; [proveedor]
+
+Ejemplos:
+/convertir 10000 ARS Blue
+/convertir 100 USD DAI Buenbit
+/convertir 50000 ARS BTC Satoshi Tango`
+
+func (h *handler) handleConvertCommand(ctx context.Context, args string) string {
+	h.logger.Info("handle convert command", zap.String("args", args))
+
+	conversion, err := h.convertService.Convert(ctx, args)
+	switch {
+	case errors.Is(err, convert.ErrInvalidFormat):
+		return convertUsageMsg
+	case errors.Is(err, currency.ErrUnknownProvider):
+		return "Proveedor desconocido, las opciones son: " + h.providerNames()
+	case errors.Is(err, convert.ErrNoRoute):
+		return "No encontramos cotizaciones para hacer esa conversión, revisa las opciones con /cotizaciones"
+	case err != nil:
+		h.logger.Error("converting", zap.Error(err))
+		return errorMsg
+	}
+
+	message, err := h.templateEngine.FormatConversionMessage(conversion)
+	if err != nil {
+		h.logger.Error("formatting conversion template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
@@ -0,0 +1,31 @@
+package reply
+
+import (
+	"context"
+
+	"coinbani/pkg/cryptodollar"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func (h *handler) handleCryptoDollarsCommand(ctx context.Context) string {
+	h.logger.Info("handle crypto dollars command")
+
+	table, err := h.cryptoDollarService.CryptoDollars(ctx)
+	switch {
+	case errors.Is(err, cryptodollar.ErrNoCryptoDollars):
+		return unavailableMsg
+	case err != nil:
+		h.logger.Error("getting crypto dollars", zap.Error(err))
+		return errorMsg
+	}
+
+	message, err := h.templateEngine.FormatCryptoDollarsMessage(table)
+	if err != nil {
+		h.logger.Error("formatting crypto dollars template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
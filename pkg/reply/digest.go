@@ -0,0 +1,68 @@
+package reply
+
+import (
+	"fmt"
+	"strings"
+
+	"coinbani/pkg/currency"
+	"coinbani/pkg/digest"
+	"coinbani/pkg/store"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const subscribeUsageMsg = `Uso: /suscribir &lt;proveedor&gt; &lt;HH:MM&gt;
+Todos los días a esa hora te enviaremos las cotizaciones del proveedor.
+
+Ejemplo: /suscribir Dolar 09:30`
+
+func (h *handler) handleSubscribeCommand(chatID int64, args string) string {
+	h.logger.Info("handle subscribe command", zap.Int64("chatID", chatID), zap.String("args", args))
+
+	sub, err := h.digestService.Subscribe(chatID, h.chatTimezone(chatID), args)
+	switch {
+	case err == nil:
+		return fmt.Sprintf("Listo, todos los días a las %s (%s) te enviaremos las cotizaciones de %s. Para cancelar usa /desuscribir", sub.Time(), sub.Timezone, sub.Provider)
+	case errors.Is(err, digest.ErrInvalidFormat):
+		return subscribeUsageMsg
+	case errors.Is(err, currency.ErrUnknownProvider):
+		return "Proveedor desconocido, las opciones son: " + h.providerNames()
+	default:
+		h.logger.Error("subscribing", zap.Error(err))
+		return errorMsg
+	}
+}
+
+func (h *handler) handleUnsubscribeCommand(chatID int64, args string) string {
+	h.logger.Info("handle unsubscribe command", zap.Int64("chatID", chatID), zap.String("args", args))
+
+	subs, err := h.digestService.Unsubscribe(chatID, strings.TrimSpace(args))
+	switch {
+	case err == nil:
+		var providers []string
+		for _, sub := range subs {
+			providers = append(providers, sub.Provider)
+		}
+		return "Cancelamos el resumen diario de " + strings.Join(providers, ", ")
+	case errors.Is(err, digest.ErrNotFound):
+		return "No tienes suscripciones, puedes crear una con /suscribir"
+	case errors.Is(err, currency.ErrUnknownProvider):
+		return "Proveedor desconocido, las opciones son: " + h.providerNames()
+	default:
+		h.logger.Error("unsubscribing", zap.Error(err))
+		return errorMsg
+	}
+}
+
+// chatTimezone returns the timezone set in the chat preferences, empty when there is none.
+func (h *handler) chatTimezone(chatID int64) string {
+	p, err := h.userStore.GetChatPreferences(chatID)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			h.logger.Error("getting chat preferences", zap.Int64("chatID", chatID), zap.Error(err))
+		}
+		return ""
+	}
+	return p.Timezone
+}
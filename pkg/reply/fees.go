@@ -0,0 +1,38 @@
+package reply
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const feesUsageMsg = "Uso: /comisiones &lt;proveedor&gt;, muestra los precios con y sin comisiones"
+
+func (h *handler) handleFeesCommand(ctx context.Context, args string) string {
+	h.logger.Info("handle fees command", zap.String("args", args))
+
+	name := strings.TrimSpace(args)
+	if name == "" {
+		return feesUsageMsg
+	}
+
+	info, found := h.currencyService.ProviderByName(name)
+	if !found {
+		return "Proveedor desconocido, las opciones son: " + h.providerNames()
+	}
+
+	lastPrices, err := h.currencyService.GetLastPrices(ctx, info.Label)
+	if err != nil {
+		h.logger.Error("getting prices", zap.Error(err))
+		return fetchErrorMessage(err)
+	}
+
+	message, err := h.templateEngine.FormatFeesMessage(lastPrices, info.Fees)
+	if err != nil {
+		h.logger.Error("formatting fees template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
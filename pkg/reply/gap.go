@@ -0,0 +1,31 @@
+package reply
+
+import (
+	"context"
+
+	"coinbani/pkg/gap"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func (h *handler) handleGapsCommand(ctx context.Context) string {
+	h.logger.Info("handle gaps command")
+
+	report, err := h.gapService.Gaps(ctx)
+	switch {
+	case errors.Is(err, gap.ErrNoGaps):
+		return unavailableMsg
+	case err != nil:
+		h.logger.Error("getting gaps", zap.Error(err))
+		return errorMsg
+	}
+
+	message, err := h.templateEngine.FormatGapsMessage(report)
+	if err != nil {
+		h.logger.Error("formatting gaps template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
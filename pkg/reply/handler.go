@@ -1,74 +1,259 @@
 package reply
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/alert"
+	"coinbani/pkg/arbitrage"
+	"coinbani/pkg/chart"
+	"coinbani/pkg/client"
+	"coinbani/pkg/compare"
+	"coinbani/pkg/convert"
+	"coinbani/pkg/cryptodollar"
 	"coinbani/pkg/currency"
+	"coinbani/pkg/digest"
+	"coinbani/pkg/gap"
+	"coinbani/pkg/history"
+	"coinbani/pkg/store"
+	"coinbani/pkg/tax"
 	"coinbani/pkg/telegram"
 
 	tb "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
 const (
-	errorMsg = "Lo sentimos, ha ocurrido un error intenta más tarde"
-)
+	errorMsg           = "Lo sentimos, ha ocurrido un error intenta más tarde"
+	unavailableMsg     = "El servicio de cotizaciones no está disponible en este momento, intenta más tarde"
+	invalidResponseMsg = "El servicio de cotizaciones devolvió datos inválidos, intenta más tarde"
+	adminOnlyMsg       = "Este comando solo está disponible para administradores"
+
+	keyboardButtonsPerRow = 2
 
-var optionsKeyboard = tb.NewReplyKeyboard(
-	tb.NewKeyboardButtonRow(
-		tb.NewKeyboardButton(currency.BBProviderLabel),
-		tb.NewKeyboardButton(currency.SatoshiTProviderLabel),
-	),
-	tb.NewKeyboardButtonRow(
-		tb.NewKeyboardButton(currency.DollarProviderLabel),
-	),
+	// lastSeenInterval is how stale the last seen time of a user can get before it's saved again
+	lastSeenInterval = 10 * time.Minute
 )
 
 type currencyService interface {
-	GetLastPrices(providerName string) (*currency.CurrencyPriceList, error)
+	Providers() []currency.ProviderInfo
+	ProviderByName(name string) (currency.ProviderInfo, bool)
+	GetLastPrices(ctx context.Context, providerName string) (*currency.CurrencyPriceList, error)
+	GetAllLastPrices(ctx context.Context) []*currency.ProviderPrices
 }
 
 type templateEngine interface {
 	FormatPricesMessage(priceList *currency.CurrencyPriceList) (string, error)
+	FormatAllPricesMessage(results []*currency.ProviderPrices) (string, error)
+	FormatFeesMessage(priceList *currency.CurrencyPriceList, fees options.FeeModel) (string, error)
+	FormatStatusMessage(statuses []client.BreakerStatus) (string, error)
+	FormatAlertsMessage(alerts []*alert.Alert) (string, error)
+	FormatHistoryMessage(pair string, period string, stats []*history.Stats) (string, error)
+	FormatComparisonMessage(c *compare.Comparison) (string, error)
+	FormatArbitrageMessage(a *arbitrage.Analysis) (string, error)
+	FormatConversionMessage(c *convert.Conversion) (string, error)
+	FormatTaxesMessage(breakdowns []*tax.Breakdown) (string, error)
+	FormatGapsMessage(r *gap.Report) (string, error)
+	FormatCryptoDollarsMessage(t *cryptodollar.Table) (string, error)
+}
+
+type statusProvider interface {
+	BreakerStatuses() []client.BreakerStatus
+}
+
+type userStore interface {
+	GetUser(id int64) (*store.User, error)
+	SaveUser(u *store.User) error
+	GetChatPreferences(chatID int64) (*store.ChatPreferences, error)
+}
+
+type alertService interface {
+	Create(ctx context.Context, chatID int64, args string) (*alert.Alert, error)
+	List(chatID int64) ([]*alert.Alert, error)
+	Delete(chatID int64, id int64) error
+}
+
+type arbitrageService interface {
+	Analyze(ctx context.Context) *arbitrage.Analysis
+	SetAlertThreshold(chatID int64, threshold currency.Money) error
+}
+
+type compareService interface {
+	Compare(ctx context.Context, pair string, side compare.Side) (*compare.Comparison, error)
+}
+
+type convertService interface {
+	Convert(ctx context.Context, args string) (*convert.Conversion, error)
+}
+
+type digestService interface {
+	Subscribe(chatID int64, timezone string, args string) (*digest.Subscription, error)
+	Unsubscribe(chatID int64, providerName string) ([]*digest.Subscription, error)
+}
+
+type taxService interface {
+	Breakdowns(ctx context.Context) ([]*tax.Breakdown, error)
+}
+
+type gapService interface {
+	Gaps(ctx context.Context) (*gap.Report, error)
+}
+
+type cryptoDollarService interface {
+	CryptoDollars(ctx context.Context) (*cryptodollar.Table, error)
+}
+
+type historyService interface {
+	GetStats(pair string, period string) ([]*history.Stats, error)
+	GetSeries(pair string, period string) ([]*history.Series, error)
+}
+
+type chartRenderer interface {
+	RenderPNG(series []*chart.Series) ([]byte, *chart.Bounds, error)
+}
+
+// Deps are the services the handler replies with.
+type Deps struct {
+	Bot                 telegram.Bot
+	UserStore           userStore
+	CurrencyService     currencyService
+	AlertService        alertService
+	CompareService      compareService
+	ArbitrageService    arbitrageService
+	ConvertService      convertService
+	DigestService       digestService
+	HistoryService      historyService
+	TaxService          taxService
+	GapService          gapService
+	CryptoDollarService cryptoDollarService
+	ChartRenderer       chartRenderer
+	TemplateEngine      templateEngine
+	StatusProvider      statusProvider
 }
 
 type handler struct {
-	bot             telegram.Bot
-	currencyService currencyService
-	templateEngine  templateEngine
-	logger          *zap.Logger
+	config              *options.BotConfig
+	bot                 telegram.Bot
+	userStore           userStore
+	currencyService     currencyService
+	alertService        alertService
+	compareService      compareService
+	arbitrageService    arbitrageService
+	convertService      convertService
+	digestService       digestService
+	historyService      historyService
+	taxService          taxService
+	gapService          gapService
+	cryptoDollarService cryptoDollarService
+	chartRenderer       chartRenderer
+	templateEngine      templateEngine
+	statusProvider      statusProvider
+	logger              *zap.Logger
 }
 
-func NewHandler(b telegram.Bot, cs currencyService, t templateEngine, l *zap.Logger) *handler {
+func NewHandler(c *options.BotConfig, d *Deps, l *zap.Logger) *handler {
 	return &handler{
-		bot:             b,
-		currencyService: cs,
-		templateEngine:  t,
-		logger:          l,
+		config:              c,
+		bot:                 d.Bot,
+		userStore:           d.UserStore,
+		currencyService:     d.CurrencyService,
+		alertService:        d.AlertService,
+		compareService:      d.CompareService,
+		arbitrageService:    d.ArbitrageService,
+		convertService:      d.ConvertService,
+		digestService:       d.DigestService,
+		historyService:      d.HistoryService,
+		taxService:          d.TaxService,
+		gapService:          d.GapService,
+		cryptoDollarService: d.CryptoDollarService,
+		chartRenderer:       d.ChartRenderer,
+		templateEngine:      d.TemplateEngine,
+		statusProvider:      d.StatusProvider,
+		logger:              l,
 	}
 }
 
-func (h *handler) HandleReply(update tb.Update) {
+func (h *handler) HandleReply(ctx context.Context, update tb.Update) {
 	if update.Message == nil { // ignore any non-Message Updates
 		return
 	}
 
 	h.logger.Debug(fmt.Sprintf("handling message [%s] %s", update.Message.From.UserName, update.Message.Text))
+	h.recordUser(update.Message.From)
 
 	msg := tb.NewMessage(update.Message.Chat.ID, "")
 
 	if update.Message.IsCommand() {
+		chatID := update.Message.Chat.ID
+		args := update.Message.CommandArguments()
+
 		switch update.Message.Command() {
 		case "cotizaciones":
 			msg.Text = "Selecciona una opción para ver las cotizaciones:"
-			msg.ReplyMarkup = optionsKeyboard
+			msg.ReplyMarkup = h.providersKeyboard()
+		case "todas":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleAllProvidersCommand(ctx)
+		case "comisiones":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleFeesCommand(ctx, args)
+		case "mejor":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleCompareCommand(ctx, args)
+		case "arbitraje":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleArbitrageCommand(ctx, chatID, args)
+		case "convertir":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleConvertCommand(ctx, args)
+		case "impuestos":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleTaxesCommand(ctx)
+		case "brecha":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleGapsCommand(ctx)
+		case "dolarcripto":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleCryptoDollarsCommand(ctx)
+		case "estado":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleStatusCommand(chatID)
+		case "alerta":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleCreateAlertCommand(ctx, chatID, args)
+		case "alertas":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleListAlertsCommand(chatID)
+		case "borrar_alerta":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleDeleteAlertCommand(chatID, args)
+		case "suscribir":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleSubscribeCommand(chatID, args)
+		case "desuscribir":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleUnsubscribeCommand(chatID, args)
+		case "historial":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleHistoryCommand(args)
+		case "grafico":
+			msg.ParseMode = tb.ModeHTML
+			msg.Text = h.handleChartCommand(chatID, args)
 		default:
-			msg.Text = "Intenta con /cotizaciones"
+			msg.Text = "Intenta con /cotizaciones o /todas"
 		}
 	} else {
 		// handle keyboard button text
 		msg.ParseMode = tb.ModeHTML
-		msg.Text = h.handleProviderCommand(update.Message.Text)
+		msg.Text = h.handleProviderCommand(ctx, update.Message.Text)
+	}
+
+	// the reply was already sent, e.g. as a photo
+	if msg.Text == "" {
+		return
 	}
 
 	_, err := h.bot.Send(msg)
@@ -77,12 +262,38 @@ func (h *handler) HandleReply(update tb.Update) {
 	}
 }
 
-func (h *handler) handleProviderCommand(providerName string) string {
+func (h *handler) recordUser(from *tb.User) {
+	if from == nil {
+		return
+	}
+
+	now := time.Now()
+	u, err := h.userStore.GetUser(int64(from.ID))
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			h.logger.Error("getting user", zap.Int("userID", from.ID), zap.Error(err))
+			return
+		}
+		u = &store.User{ID: int64(from.ID), FirstSeen: now}
+	}
+
+	if u.UserName == from.UserName && now.Sub(u.LastSeen) < lastSeenInterval {
+		return
+	}
+
+	u.UserName = from.UserName
+	u.LastSeen = now
+	if err := h.userStore.SaveUser(u); err != nil {
+		h.logger.Error("saving user", zap.Int("userID", from.ID), zap.Error(err))
+	}
+}
+
+func (h *handler) handleProviderCommand(ctx context.Context, providerName string) string {
 	h.logger.Info(fmt.Sprintf("handle provider command: %s", providerName))
-	lastPrices, err := h.currencyService.GetLastPrices(providerName)
+	lastPrices, err := h.currencyService.GetLastPrices(ctx, providerName)
 	if err != nil {
 		h.logger.Error("getting prices", zap.Error(err))
-		return errorMsg
+		return fetchErrorMessage(err)
 	}
 
 	message, err := h.templateEngine.FormatPricesMessage(lastPrices)
@@ -93,3 +304,71 @@ func (h *handler) handleProviderCommand(providerName string) string {
 
 	return message
 }
+
+func (h *handler) handleAllProvidersCommand(ctx context.Context) string {
+	h.logger.Info("handle all providers command")
+	results := h.currencyService.GetAllLastPrices(ctx)
+
+	message, err := h.templateEngine.FormatAllPricesMessage(results)
+	if err != nil {
+		h.logger.Error("formatting all prices template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
+
+func (h *handler) handleStatusCommand(chatID int64) string {
+	h.logger.Info("handle status command")
+	if !h.isAdmin(chatID) {
+		return adminOnlyMsg
+	}
+
+	message, err := h.templateEngine.FormatStatusMessage(h.statusProvider.BreakerStatuses())
+	if err != nil {
+		h.logger.Error("formatting status template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
+
+func (h *handler) isAdmin(chatID int64) bool {
+	for _, id := range h.config.AdminChatIDs {
+		if id == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *handler) providersKeyboard() tb.ReplyKeyboardMarkup {
+	var rows [][]tb.KeyboardButton
+	var row []tb.KeyboardButton
+
+	for _, p := range h.currencyService.Providers() {
+		row = append(row, tb.NewKeyboardButton(p.Label))
+		if len(row) == keyboardButtonsPerRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	return tb.NewReplyKeyboard(rows...)
+}
+
+// fetchErrorMessage tells the user whether the upstream service is down or returned unexpected data.
+func fetchErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, client.ErrCircuitOpen), errors.Is(err, client.ErrStatus), errors.Is(err, context.DeadlineExceeded):
+		return unavailableMsg
+	case errors.Is(err, client.ErrDecode), errors.Is(err, client.ErrTooLarge):
+		return invalidResponseMsg
+	default:
+		return errorMsg
+	}
+}
@@ -0,0 +1,38 @@
+package reply
+
+import (
+	"coinbani/pkg/history"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const historyUsageMsg = "Uso: /historial &lt;par&gt; [24h|7d|30d], por ejemplo /historial Blue 7d"
+
+func (h *handler) handleHistoryCommand(args string) string {
+	h.logger.Info("handle history command", zap.String("args", args))
+
+	pair, period, ok := parsePairAndPeriod(args)
+	if !ok {
+		return historyUsageMsg
+	}
+
+	stats, err := h.historyService.GetStats(pair, period)
+	switch {
+	case errors.Is(err, history.ErrInvalidPeriod):
+		return historyUsageMsg
+	case errors.Is(err, history.ErrUnknownPair):
+		return "Ningún proveedor cotiza ese par, revisa las opciones con /cotizaciones"
+	case err != nil:
+		h.logger.Error("getting price history", zap.Error(err))
+		return errorMsg
+	}
+
+	message, err := h.templateEngine.FormatHistoryMessage(pair, period, stats)
+	if err != nil {
+		h.logger.Error("formatting history template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
@@ -0,0 +1,31 @@
+package reply
+
+import (
+	"context"
+
+	"coinbani/pkg/tax"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func (h *handler) handleTaxesCommand(ctx context.Context) string {
+	h.logger.Info("handle taxes command")
+
+	breakdowns, err := h.taxService.Breakdowns(ctx)
+	switch {
+	case errors.Is(err, tax.ErrNoOfficialPrice):
+		return "El precio del dólar oficial no está disponible en este momento, intenta más tarde"
+	case err != nil:
+		h.logger.Error("getting tax breakdowns", zap.Error(err))
+		return fetchErrorMessage(err)
+	}
+
+	message, err := h.templateEngine.FormatTaxesMessage(breakdowns)
+	if err != nil {
+		h.logger.Error("formatting taxes template", zap.Error(err))
+		return errorMsg
+	}
+
+	return message
+}
@@ -0,0 +1,288 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/alert"
+	"coinbani/pkg/digest"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltStore persists the state as JSON values in a BoltDB file.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func NewBoltStore(c *options.StoreConfig) (*boltStore, error) {
+	db, err := bolt.Open(c.Path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening store file %s", c.Path)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "migrating store")
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) SaveUser(u *User) error {
+	return s.put(usersBucket, itob(u.ID), u)
+}
+
+func (s *boltStore) GetUser(id int64) (*User, error) {
+	var u User
+	if err := s.get(usersBucket, itob(id), &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (s *boltStore) SaveChatPreferences(p *ChatPreferences) error {
+	return s.put(chatsBucket, itob(p.ChatID), p)
+}
+
+func (s *boltStore) GetChatPreferences(chatID int64) (*ChatPreferences, error) {
+	var p ChatPreferences
+	if err := s.get(chatsBucket, itob(chatID), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *boltStore) ListChatPreferences() ([]*ChatPreferences, error) {
+	var preferences []*ChatPreferences
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(chatsBucket).ForEach(func(k, v []byte) error {
+			var p ChatPreferences
+			if err := json.Unmarshal(v, &p); err != nil {
+				return errors.Wrapf(err, "decoding chat preferences %d", btoi(k))
+			}
+			preferences = append(preferences, &p)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing chat preferences")
+	}
+
+	return preferences, nil
+}
+
+func (s *boltStore) AddAlert(a *alert.Alert) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(alertsBucket)
+		id, err := b.NextSequence()
+		if err != nil {
+			return errors.Wrap(err, "generating alert id")
+		}
+
+		a.ID = int64(id)
+		return putJSON(b, itob(a.ID), a)
+	})
+}
+
+func (s *boltStore) ListAlerts() ([]*alert.Alert, error) {
+	return s.filterAlerts(func(a *alert.Alert) bool { return true })
+}
+
+func (s *boltStore) ListChatAlerts(chatID int64) ([]*alert.Alert, error) {
+	return s.filterAlerts(func(a *alert.Alert) bool { return a.ChatID == chatID })
+}
+
+func (s *boltStore) filterAlerts(keep func(a *alert.Alert) bool) ([]*alert.Alert, error) {
+	var alerts []*alert.Alert
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(alertsBucket).ForEach(func(k, v []byte) error {
+			var a alert.Alert
+			if err := json.Unmarshal(v, &a); err != nil {
+				return errors.Wrapf(err, "decoding alert %d", btoi(k))
+			}
+			if keep(&a) {
+				alerts = append(alerts, &a)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing alerts")
+	}
+
+	return alerts, nil
+}
+
+func (s *boltStore) DeleteAlert(chatID int64, id int64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(alertsBucket)
+
+		v := b.Get(itob(id))
+		if v == nil {
+			return alert.ErrNotFound
+		}
+
+		var a alert.Alert
+		if err := json.Unmarshal(v, &a); err != nil {
+			return errors.Wrapf(err, "decoding alert %d", id)
+		}
+		if a.ChatID != chatID {
+			return alert.ErrNotFound
+		}
+
+		return b.Delete(itob(id))
+	})
+}
+
+func (s *boltStore) SaveSubscription(sub *digest.Subscription) error {
+	return s.put(subscriptionsBucket, subscriptionBoltKey(sub.ChatID, sub.Provider), sub)
+}
+
+func (s *boltStore) ListSubscriptions() ([]*digest.Subscription, error) {
+	return s.listSubscriptions(nil)
+}
+
+func (s *boltStore) ListChatSubscriptions(chatID int64) ([]*digest.Subscription, error) {
+	return s.listSubscriptions(itob(chatID))
+}
+
+// listSubscriptions returns the subscriptions whose key starts with prefix, keys are the chat id followed by the provider.
+func (s *boltStore) listSubscriptions(prefix []byte) ([]*digest.Subscription, error) {
+	var subs []*digest.Subscription
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(subscriptionsBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var sub digest.Subscription
+			if err := json.Unmarshal(v, &sub); err != nil {
+				return errors.Wrapf(err, "decoding subscription %q", k)
+			}
+			subs = append(subs, &sub)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing subscriptions")
+	}
+
+	return subs, nil
+}
+
+func (s *boltStore) DeleteSubscription(chatID int64, provider string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(subscriptionsBucket)
+		key := subscriptionBoltKey(chatID, provider)
+		if b.Get(key) == nil {
+			return digest.ErrNotFound
+		}
+		return b.Delete(key)
+	})
+}
+
+func subscriptionBoltKey(chatID int64, provider string) []byte {
+	return append(itob(chatID), provider...)
+}
+
+func (s *boltStore) AddPriceSnapshots(snapshots []*PriceSnapshot) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, snapshot := range snapshots {
+			b, err := tx.Bucket(snapshotsBucket).CreateBucketIfNotExists([]byte(snapshotKey(snapshot.Provider, snapshot.Pair)))
+			if err != nil {
+				return errors.Wrap(err, "creating pair snapshots bucket")
+			}
+			if err := putJSON(b, itob(snapshot.At.UnixNano()), snapshot); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltStore) ListPriceSnapshots(provider string, pair string, from time.Time, to time.Time) ([]*PriceSnapshot, error) {
+	var snapshots []*PriceSnapshot
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(snapshotsBucket).Bucket([]byte(snapshotKey(provider, pair)))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		max := itob(to.UnixNano())
+		for k, v := c.Seek(itob(from.UnixNano())); k != nil && bytes.Compare(k, max) < 0; k, v = c.Next() {
+			var snapshot PriceSnapshot
+			if err := json.Unmarshal(v, &snapshot); err != nil {
+				return errors.Wrap(err, "decoding price snapshot")
+			}
+			snapshots = append(snapshots, &snapshot)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing price snapshots")
+	}
+
+	return snapshots, nil
+}
+
+func (s *boltStore) DeletePriceSnapshotsBefore(before time.Time) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		snapshots := tx.Bucket(snapshotsBucket)
+		return snapshots.ForEach(func(pair []byte, _ []byte) error {
+			b := snapshots.Bucket(pair)
+			if b == nil {
+				return nil
+			}
+
+			var keys [][]byte
+			c := b.Cursor()
+			max := itob(before.UnixNano())
+			for k, _ := c.First(); k != nil && bytes.Compare(k, max) < 0; k, _ = c.Next() {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			deleted += len(keys)
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "deleting price snapshots")
+	}
+
+	return deleted, nil
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *boltStore) put(bucket []byte, key []byte, v interface{}) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(bucket), key, v)
+	})
+}
+
+func (s *boltStore) get(bucket []byte, key []byte, v interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get(key)
+		if data == nil {
+			return ErrNotFound
+		}
+		return errors.Wrapf(json.Unmarshal(data, v), "decoding %s value", bucket)
+	})
+}
+
+func putJSON(b *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "encoding %T", v)
+	}
+	return b.Put(key, data)
+}
@@ -0,0 +1,234 @@
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"coinbani/pkg/alert"
+	"coinbani/pkg/digest"
+)
+
+type subscriptionKey struct {
+	chatID   int64
+	provider string
+}
+
+type inMemoryStore struct {
+	lock        sync.RWMutex
+	users       map[int64]User
+	preferences map[int64]ChatPreferences
+	lastAlertID int64
+	alerts      map[int64]alert.Alert
+	snapshots   map[string][]PriceSnapshot
+	subs        map[subscriptionKey]digest.Subscription
+}
+
+func NewInMemoryStore() *inMemoryStore {
+	return &inMemoryStore{
+		users:       make(map[int64]User),
+		preferences: make(map[int64]ChatPreferences),
+		alerts:      make(map[int64]alert.Alert),
+		snapshots:   make(map[string][]PriceSnapshot),
+		subs:        make(map[subscriptionKey]digest.Subscription),
+	}
+}
+
+func (s *inMemoryStore) SaveUser(u *User) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.users[u.ID] = *u
+	return nil
+}
+
+func (s *inMemoryStore) GetUser(id int64) (*User, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	u, found := s.users[id]
+	if !found {
+		return nil, ErrNotFound
+	}
+	return &u, nil
+}
+
+func (s *inMemoryStore) SaveChatPreferences(p *ChatPreferences) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.preferences[p.ChatID] = *p
+	return nil
+}
+
+func (s *inMemoryStore) GetChatPreferences(chatID int64) (*ChatPreferences, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	p, found := s.preferences[chatID]
+	if !found {
+		return nil, ErrNotFound
+	}
+	return &p, nil
+}
+
+func (s *inMemoryStore) ListChatPreferences() ([]*ChatPreferences, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var preferences []*ChatPreferences
+	for _, p := range s.preferences {
+		p := p
+		preferences = append(preferences, &p)
+	}
+
+	sort.Slice(preferences, func(i, j int) bool { return preferences[i].ChatID < preferences[j].ChatID })
+	return preferences, nil
+}
+
+func (s *inMemoryStore) AddAlert(a *alert.Alert) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.lastAlertID++
+	a.ID = s.lastAlertID
+	s.alerts[a.ID] = *a
+	return nil
+}
+
+func (s *inMemoryStore) ListAlerts() ([]*alert.Alert, error) {
+	return s.filterAlerts(func(a *alert.Alert) bool { return true }), nil
+}
+
+func (s *inMemoryStore) ListChatAlerts(chatID int64) ([]*alert.Alert, error) {
+	return s.filterAlerts(func(a *alert.Alert) bool { return a.ChatID == chatID }), nil
+}
+
+func (s *inMemoryStore) filterAlerts(keep func(a *alert.Alert) bool) []*alert.Alert {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var alerts []*alert.Alert
+	for _, a := range s.alerts {
+		a := a
+		if keep(&a) {
+			alerts = append(alerts, &a)
+		}
+	}
+
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+	return alerts
+}
+
+func (s *inMemoryStore) DeleteAlert(chatID int64, id int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	a, found := s.alerts[id]
+	if !found || a.ChatID != chatID {
+		return alert.ErrNotFound
+	}
+
+	delete(s.alerts, id)
+	return nil
+}
+
+func (s *inMemoryStore) SaveSubscription(sub *digest.Subscription) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.subs[subscriptionKey{sub.ChatID, sub.Provider}] = *sub
+	return nil
+}
+
+func (s *inMemoryStore) ListSubscriptions() ([]*digest.Subscription, error) {
+	return s.filterSubscriptions(func(sub *digest.Subscription) bool { return true }), nil
+}
+
+func (s *inMemoryStore) ListChatSubscriptions(chatID int64) ([]*digest.Subscription, error) {
+	return s.filterSubscriptions(func(sub *digest.Subscription) bool { return sub.ChatID == chatID }), nil
+}
+
+func (s *inMemoryStore) filterSubscriptions(keep func(sub *digest.Subscription) bool) []*digest.Subscription {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var subs []*digest.Subscription
+	for _, sub := range s.subs {
+		sub := sub
+		if keep(&sub) {
+			subs = append(subs, &sub)
+		}
+	}
+
+	// sorted by chat and provider like the bolt keys of positive chat ids
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].ChatID != subs[j].ChatID {
+			return subs[i].ChatID < subs[j].ChatID
+		}
+		return subs[i].Provider < subs[j].Provider
+	})
+	return subs
+}
+
+func (s *inMemoryStore) DeleteSubscription(chatID int64, provider string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := subscriptionKey{chatID, provider}
+	if _, found := s.subs[key]; !found {
+		return digest.ErrNotFound
+	}
+
+	delete(s.subs, key)
+	return nil
+}
+
+func (s *inMemoryStore) AddPriceSnapshots(snapshots []*PriceSnapshot) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, snapshot := range snapshots {
+		key := snapshotKey(snapshot.Provider, snapshot.Pair)
+		list := append(s.snapshots[key], *snapshot)
+		sort.SliceStable(list, func(i, j int) bool { return list[i].At.Before(list[j].At) })
+		s.snapshots[key] = list
+	}
+	return nil
+}
+
+func (s *inMemoryStore) ListPriceSnapshots(provider string, pair string, from time.Time, to time.Time) ([]*PriceSnapshot, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var snapshots []*PriceSnapshot
+	for _, snapshot := range s.snapshots[snapshotKey(provider, pair)] {
+		snapshot := snapshot
+		if !snapshot.At.Before(from) && snapshot.At.Before(to) {
+			snapshots = append(snapshots, &snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+func (s *inMemoryStore) DeletePriceSnapshotsBefore(before time.Time) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var deleted int
+	for key, list := range s.snapshots {
+		// snapshots are kept sorted by time
+		i := sort.Search(len(list), func(i int) bool { return !list[i].At.Before(before) })
+		deleted += i
+		s.snapshots[key] = list[i:]
+	}
+	return deleted, nil
+}
+
+func (s *inMemoryStore) Close() error {
+	return nil
+}
+
+func snapshotKey(provider string, pair string) string {
+	return provider + "|" + pair
+}
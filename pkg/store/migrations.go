@@ -0,0 +1,107 @@
+package store
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metaBucket          = []byte("meta")
+	usersBucket         = []byte("users")
+	chatsBucket         = []byte("chats")
+	alertsBucket        = []byte("alerts")
+	snapshotsBucket     = []byte("price_snapshots")
+	subscriptionsBucket = []byte("subscriptions")
+
+	schemaVersionKey = []byte("schema_version")
+)
+
+type migration struct {
+	desc string
+	up   func(tx *bolt.Tx) error
+}
+
+// migrations are applied in order, the schema version is the number of applied migrations.
+// Never modify or reorder an existing migration, append a new one instead.
+var migrations = []migration{
+	{
+		desc: "create users, chats, alerts and price snapshots buckets",
+		up: func(tx *bolt.Tx) error {
+			return createBuckets(tx, usersBucket, chatsBucket, alertsBucket, snapshotsBucket)
+		},
+	},
+	{
+		desc: "create subscriptions bucket",
+		up: func(tx *bolt.Tx) error {
+			return createBuckets(tx, subscriptionsBucket)
+		},
+	},
+}
+
+// migrate applies the pending migrations, each one in its own transaction.
+func migrate(db *bolt.DB) error {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(metaBucket)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "creating meta bucket")
+	}
+
+	for {
+		done := false
+		err := db.Update(func(tx *bolt.Tx) error {
+			meta := tx.Bucket(metaBucket)
+			version := schemaVersion(meta)
+			if version > len(migrations) {
+				return errors.Errorf("store schema version %d is newer than the supported %d", version, len(migrations))
+			}
+			if version == len(migrations) {
+				done = true
+				return nil
+			}
+
+			m := migrations[version]
+			if err := m.up(tx); err != nil {
+				return errors.Wrapf(err, "applying migration %d: %s", version+1, m.desc)
+			}
+			return meta.Put(schemaVersionKey, itob(int64(version+1)))
+		})
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+func schemaVersion(meta *bolt.Bucket) int {
+	v := meta.Get(schemaVersionKey)
+	if v == nil {
+		return 0
+	}
+	return int(btoi(v))
+}
+
+func createBuckets(tx *bolt.Tx, names ...[]byte) error {
+	for _, name := range names {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return errors.Wrapf(err, "creating bucket %s", name)
+		}
+	}
+	return nil
+}
+
+// itob encodes the value big endian so keys sort numerically.
+func itob(v int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(v))
+	return b
+}
+
+func btoi(b []byte) int64 {
+	return int64(binary.BigEndian.Uint64(b))
+}
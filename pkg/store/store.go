@@ -0,0 +1,79 @@
+package store
+
+import (
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/alert"
+	"coinbani/pkg/currency"
+	"coinbani/pkg/digest"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type User struct {
+	ID        int64
+	UserName  string
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+type ChatPreferences struct {
+	ChatID   int64
+	Timezone string
+	// ArbitrageThreshold is the minimum profit percent of the arbitrage alert, 0 when disabled
+	ArbitrageThreshold currency.Money
+}
+
+// PriceSnapshot is the quote of a pair at a given time.
+type PriceSnapshot struct {
+	Provider string
+	Pair     string
+	BidPrice currency.Money
+	AskPrice currency.Money
+	At       time.Time
+}
+
+// Store persists the bot state. Alerts and subscriptions are deleted with alert.ErrNotFound and
+// digest.ErrNotFound so callers of those packages don't depend on the store implementation.
+type Store interface {
+	SaveUser(u *User) error
+	GetUser(id int64) (*User, error)
+
+	SaveChatPreferences(p *ChatPreferences) error
+	GetChatPreferences(chatID int64) (*ChatPreferences, error)
+	ListChatPreferences() ([]*ChatPreferences, error)
+
+	AddAlert(a *alert.Alert) error
+	ListAlerts() ([]*alert.Alert, error)
+	ListChatAlerts(chatID int64) ([]*alert.Alert, error)
+	DeleteAlert(chatID int64, id int64) error
+
+	// SaveSubscription creates or replaces the subscription of the chat to the provider
+	SaveSubscription(s *digest.Subscription) error
+	ListSubscriptions() ([]*digest.Subscription, error)
+	ListChatSubscriptions(chatID int64) ([]*digest.Subscription, error)
+	DeleteSubscription(chatID int64, provider string) error
+
+	AddPriceSnapshots(snapshots []*PriceSnapshot) error
+	// ListPriceSnapshots returns the snapshots of a pair taken in [from, to), oldest first
+	ListPriceSnapshots(provider string, pair string, from time.Time, to time.Time) ([]*PriceSnapshot, error)
+	// DeletePriceSnapshotsBefore deletes the snapshots of every pair taken before the given time
+	DeletePriceSnapshotsBefore(before time.Time) (int, error)
+
+	Close() error
+}
+
+// New creates the store for the configured driver.
+func New(c *options.StoreConfig) (Store, error) {
+	switch c.Driver {
+	case "bolt":
+		return NewBoltStore(c)
+	case "memory":
+		return NewInMemoryStore(), nil
+	default:
+		return nil, errors.Errorf("unknown store driver %s", c.Driver)
+	}
+}
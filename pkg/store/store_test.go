@@ -0,0 +1,228 @@
+package store
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/alert"
+	"coinbani/pkg/currency"
+	"coinbani/pkg/digest"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+func testStores(t *testing.T) map[string]Store {
+	bs, err := NewBoltStore(&options.StoreConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatalf("NewBoltStore() error = %v", err)
+	}
+	t.Cleanup(func() { bs.Close() })
+
+	return map[string]Store{
+		"memory": NewInMemoryStore(),
+		"bolt":   bs,
+	}
+}
+
+func TestStore_users(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.GetUser(1); err != ErrNotFound {
+				t.Fatalf("GetUser() error = %v, want %v", err, ErrNotFound)
+			}
+
+			u := &User{ID: 1, UserName: "satoshi", FirstSeen: time.Unix(100, 0).UTC(), LastSeen: time.Unix(200, 0).UTC()}
+			if err := s.SaveUser(u); err != nil {
+				t.Fatalf("SaveUser() error = %v", err)
+			}
+
+			got, err := s.GetUser(1)
+			if err != nil {
+				t.Fatalf("GetUser() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, u) {
+				t.Errorf("GetUser() = %+v, want %+v", got, u)
+			}
+		})
+	}
+}
+
+func TestStore_alerts(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			alerts := []*alert.Alert{
+				{ChatID: 1, Pair: "Blue", Provider: "Dolar", Operator: alert.OperatorAbove, Value: currency.MustParseMoney("150")},
+				{ChatID: 2, Pair: "DAI/ARS", Provider: "Buenbit", Operator: alert.OperatorBelow, Value: currency.MustParseMoney("-5"), Percent: true},
+				{ChatID: 1, Pair: "MEP", Provider: "Dolar", Operator: alert.OperatorBelow, Value: currency.MustParseMoney("120")},
+			}
+			for _, a := range alerts {
+				if err := s.AddAlert(a); err != nil {
+					t.Fatalf("AddAlert() error = %v", err)
+				}
+			}
+			if alerts[0].ID == alerts[1].ID || alerts[1].ID == alerts[2].ID {
+				t.Fatalf("AddAlert() assigned duplicated ids")
+			}
+
+			chatAlerts, err := s.ListChatAlerts(1)
+			if err != nil {
+				t.Fatalf("ListChatAlerts() error = %v", err)
+			}
+			if !reflect.DeepEqual(chatAlerts, []*alert.Alert{alerts[0], alerts[2]}) {
+				t.Errorf("ListChatAlerts() = %+v", chatAlerts)
+			}
+
+			if err := s.DeleteAlert(2, alerts[0].ID); !errors.Is(err, alert.ErrNotFound) {
+				t.Errorf("DeleteAlert() from another chat error = %v, want %v", err, alert.ErrNotFound)
+			}
+			if err := s.DeleteAlert(1, alerts[0].ID); err != nil {
+				t.Errorf("DeleteAlert() error = %v", err)
+			}
+
+			all, err := s.ListAlerts()
+			if err != nil {
+				t.Fatalf("ListAlerts() error = %v", err)
+			}
+			if !reflect.DeepEqual(all, alerts[1:]) {
+				t.Errorf("ListAlerts() = %+v, want %+v", all, alerts[1:])
+			}
+		})
+	}
+}
+
+func TestStore_subscriptions(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			subs := []*digest.Subscription{
+				{ChatID: 1, Provider: "Dolar", Hour: 9, Timezone: "America/Argentina/Buenos_Aires"},
+				{ChatID: 1, Provider: "Buenbit", Hour: 18, Minute: 30, Timezone: "America/Argentina/Buenos_Aires"},
+				{ChatID: 2, Provider: "Dolar", Hour: 10, Timezone: "UTC"},
+			}
+			for _, sub := range subs {
+				if err := s.SaveSubscription(sub); err != nil {
+					t.Fatalf("SaveSubscription() error = %v", err)
+				}
+			}
+
+			// subscribing again to the same provider replaces the subscription
+			subs[0].Hour = 8
+			if err := s.SaveSubscription(subs[0]); err != nil {
+				t.Fatalf("SaveSubscription() error = %v", err)
+			}
+
+			chatSubs, err := s.ListChatSubscriptions(1)
+			if err != nil {
+				t.Fatalf("ListChatSubscriptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(chatSubs, []*digest.Subscription{subs[1], subs[0]}) {
+				t.Errorf("ListChatSubscriptions() = %+v", chatSubs)
+			}
+
+			if err := s.DeleteSubscription(2, "Buenbit"); !errors.Is(err, digest.ErrNotFound) {
+				t.Errorf("DeleteSubscription() of a missing subscription error = %v, want %v", err, digest.ErrNotFound)
+			}
+			if err := s.DeleteSubscription(1, "Dolar"); err != nil {
+				t.Errorf("DeleteSubscription() error = %v", err)
+			}
+
+			all, err := s.ListSubscriptions()
+			if err != nil {
+				t.Fatalf("ListSubscriptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(all, subs[1:]) {
+				t.Errorf("ListSubscriptions() = %+v, want %+v", all, subs[1:])
+			}
+		})
+	}
+}
+
+func TestStore_priceSnapshots(t *testing.T) {
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var snapshots []*PriceSnapshot
+			for i := 0; i < 5; i++ {
+				snapshots = append(snapshots, &PriceSnapshot{
+					Provider: "Dolar",
+					Pair:     "Blue",
+					BidPrice: currency.MoneyFromInt(int64(120 + i)),
+					AskPrice: currency.MoneyFromInt(int64(125 + i)),
+					At:       start.Add(time.Duration(i) * time.Hour),
+				})
+			}
+			other := &PriceSnapshot{Provider: "Dolar", Pair: "MEP", AskPrice: currency.MustParseMoney("110"), At: start}
+			if err := s.AddPriceSnapshots(append(snapshots, other)); err != nil {
+				t.Fatalf("AddPriceSnapshots() error = %v", err)
+			}
+
+			got, err := s.ListPriceSnapshots("Dolar", "Blue", start.Add(time.Hour), start.Add(4*time.Hour))
+			if err != nil {
+				t.Fatalf("ListPriceSnapshots() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, snapshots[1:4]) {
+				t.Errorf("ListPriceSnapshots() = %+v, want %+v", got, snapshots[1:4])
+			}
+		})
+	}
+}
+
+func TestStore_deletePriceSnapshotsBefore(t *testing.T) {
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var snapshots []*PriceSnapshot
+			for i := 0; i < 3; i++ {
+				at := start.Add(time.Duration(i) * time.Hour)
+				snapshots = append(snapshots,
+					&PriceSnapshot{Provider: "Dolar", Pair: "Blue", AskPrice: currency.MoneyFromInt(int64(125 + i)), At: at},
+					&PriceSnapshot{Provider: "Dolar", Pair: "MEP", AskPrice: currency.MoneyFromInt(int64(110 + i)), At: at},
+				)
+			}
+			if err := s.AddPriceSnapshots(snapshots); err != nil {
+				t.Fatalf("AddPriceSnapshots() error = %v", err)
+			}
+
+			deleted, err := s.DeletePriceSnapshotsBefore(start.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("DeletePriceSnapshotsBefore() error = %v", err)
+			}
+			if deleted != 2 {
+				t.Errorf("DeletePriceSnapshotsBefore() = %d, want 2", deleted)
+			}
+
+			got, err := s.ListPriceSnapshots("Dolar", "MEP", start, start.Add(3*time.Hour))
+			if err != nil {
+				t.Fatalf("ListPriceSnapshots() error = %v", err)
+			}
+			if want := []*PriceSnapshot{snapshots[3], snapshots[5]}; !reflect.DeepEqual(got, want) {
+				t.Errorf("ListPriceSnapshots() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func Test_migrate(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("opening db: %v", err)
+	}
+	defer db.Close()
+
+	// migrating twice must be a no-op
+	for i := 0; i < 2; i++ {
+		if err := migrate(db); err != nil {
+			t.Fatalf("migrate() error = %v", err)
+		}
+	}
+
+	db.View(func(tx *bolt.Tx) error {
+		if got := schemaVersion(tx.Bucket(metaBucket)); got != len(migrations) {
+			t.Errorf("schema version = %d, want %d", got, len(migrations))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,152 @@
+package tax
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+	"time"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed default_rules.json
+var defaultRules []byte
+
+// dateLocation is the Argentina time zone, rules start and end at local midnight
+var dateLocation = time.FixedZone("ART", -3*60*60)
+
+// Date is a day formatted as 2006-01-02.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", s, dateLocation)
+	if err != nil {
+		return errors.Wrapf(err, "parsing date %s", s)
+	}
+	d.Time = t
+	return nil
+}
+
+// Rule is a tax charged as a percent of the official price between From and Until, a zero
+// Until means the rule is still in force. A tax changing its rate is a new rule with the same name.
+type Rule struct {
+	Name    string         `json:"name"`
+	Percent currency.Money `json:"percent"`
+	From    Date           `json:"from"`
+	Until   Date           `json:"until"`
+}
+
+// ActiveAt reports whether the rule is in force at t.
+func (r *Rule) ActiveAt(t time.Time) bool {
+	return !t.Before(r.From.Time) && (r.Until.IsZero() || t.Before(r.Until.Time))
+}
+
+// Row is a dollar derived from the official one, e.g. Ahorro, with the taxes it pays.
+type Row struct {
+	Name  string   `json:"name"`
+	Rules []string `json:"rules"`
+}
+
+// AppliedTax is a tax in force charged on the official price.
+type AppliedTax struct {
+	Name    string
+	Percent currency.Money
+	Amount  currency.Money
+}
+
+// Breakdown is the price of a derived dollar with the detail of its taxes.
+type Breakdown struct {
+	Row   string
+	Base  currency.Money
+	Taxes []*AppliedTax
+	Total currency.Money
+}
+
+// Rules holds the tax rules and the derived dollars built from them.
+type Rules struct {
+	Rules []*Rule `json:"rules"`
+	Rows  []*Row  `json:"rows"`
+}
+
+// Load reads the rules file, the embedded defaults are used when path is empty.
+func Load(path string) (*Rules, error) {
+	data := defaultRules
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading tax rules file")
+		}
+	}
+
+	return Parse(data)
+}
+
+// Parse decodes and validates the rules.
+func Parse(data []byte) (*Rules, error) {
+	var r Rules
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, errors.Wrap(err, "decoding tax rules")
+	}
+
+	names := make(map[string]bool)
+	for _, rule := range r.Rules {
+		if rule.Name == "" || rule.From.IsZero() {
+			return nil, errors.Errorf("tax rule %q without name or start date", rule.Name)
+		}
+		if !rule.Until.IsZero() && !rule.Until.After(rule.From.Time) {
+			return nil, errors.Errorf("tax rule %s ends before it starts", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+
+	for _, row := range r.Rows {
+		for _, name := range row.Rules {
+			if !names[name] {
+				return nil, errors.Errorf("row %s uses unknown tax rule %s", row.Name, name)
+			}
+		}
+	}
+
+	return &r, nil
+}
+
+// RowNames returns the names of the derived dollars in the configured order.
+func (r *Rules) RowNames() []string {
+	var names []string
+	for _, row := range r.Rows {
+		names = append(names, row.Name)
+	}
+	return names
+}
+
+// Apply computes every derived dollar from the official price with the rules in force at t.
+// Taxes don't compound, each one is a percent of the official price.
+func (r *Rules) Apply(base currency.Money, at time.Time) []*Breakdown {
+	var breakdowns []*Breakdown
+	for _, row := range r.Rows {
+		b := &Breakdown{Row: row.Name, Base: base, Total: base}
+		for _, name := range row.Rules {
+			for _, rule := range r.Rules {
+				if rule.Name != name || !rule.ActiveAt(at) {
+					continue
+				}
+
+				amount := base.Mul(rule.Percent).Div(currency.MoneyFromInt(100)).Round(2)
+				b.Taxes = append(b.Taxes, &AppliedTax{Name: rule.Name, Percent: rule.Percent, Amount: amount})
+				b.Total = b.Total.Add(amount)
+			}
+		}
+		breakdowns = append(breakdowns, b)
+	}
+	return breakdowns
+}
@@ -0,0 +1,121 @@
+package tax
+
+import (
+	"testing"
+	"time"
+
+	"coinbani/pkg/currency"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "embedded defaults",
+			data: string(defaultRules),
+		},
+		{
+			name:    "invalid json",
+			data:    `{"rules": [}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			data:    `{"rules": [{"name": "PAIS", "percent": 30, "from": "23/12/2019"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "rule without start date",
+			data:    `{"rules": [{"name": "PAIS", "percent": 30}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "rule ending before it starts",
+			data:    `{"rules": [{"name": "PAIS", "percent": 30, "from": "2020-01-01", "until": "2019-01-01"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "row with unknown rule",
+			data:    `{"rules": [{"name": "PAIS", "percent": 30, "from": "2020-01-01"}], "rows": [{"name": "Ahorro", "rules": ["PAIS", "Ganancias"]}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules, err := Parse(defaultRules)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	base := currency.MustParseMoney("100")
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		totals map[string]string
+	}{
+		{
+			name:   "before any tax",
+			at:     time.Date(2019, 12, 1, 12, 0, 0, 0, dateLocation),
+			totals: map[string]string{"Ahorro": "100", "Tarjeta": "100", "Turista": "100"},
+		},
+		{
+			name:   "PAIS only",
+			at:     time.Date(2020, 1, 10, 12, 0, 0, 0, dateLocation),
+			totals: map[string]string{"Ahorro": "130", "Tarjeta": "130", "Turista": "130"},
+		},
+		{
+			name:   "bienes personales on cards",
+			at:     time.Date(2023, 1, 10, 12, 0, 0, 0, dateLocation),
+			totals: map[string]string{"Ahorro": "165", "Tarjeta": "190", "Turista": "190"},
+		},
+		{
+			name:   "ganancias raised to 45",
+			at:     time.Date(2023, 7, 24, 0, 0, 0, 0, dateLocation),
+			totals: map[string]string{"Ahorro": "175", "Tarjeta": "200", "Turista": "200"},
+		},
+		{
+			name:   "PAIS removed",
+			at:     time.Date(2025, 3, 1, 12, 0, 0, 0, dateLocation),
+			totals: map[string]string{"Ahorro": "130", "Tarjeta": "130", "Turista": "130"},
+		},
+		{
+			name:   "ganancias removed for savings",
+			at:     time.Date(2025, 4, 14, 0, 0, 0, 0, dateLocation),
+			totals: map[string]string{"Ahorro": "100", "Tarjeta": "130", "Turista": "130"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules.Apply(base, tt.at)
+			if len(got) != len(tt.totals) {
+				t.Fatalf("Apply() returned %d rows, want %d", len(got), len(tt.totals))
+			}
+			for _, b := range got {
+				want := currency.MustParseMoney(tt.totals[b.Row])
+				if !b.Total.Equal(want) {
+					t.Errorf("Apply() %s total = %s, want %s", b.Row, b.Total, want)
+				}
+
+				sum := b.Base
+				for _, tax := range b.Taxes {
+					sum = sum.Add(tax.Amount)
+				}
+				if !sum.Equal(b.Total) {
+					t.Errorf("Apply() %s taxes add up to %s, total %s", b.Row, sum, b.Total)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package tax
+
+import (
+	"context"
+	"time"
+
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+var ErrNoOfficialPrice = errors.New("official dollar price not available")
+
+type pricesFetcher interface {
+	GetLastPrices(ctx context.Context, providerName string) (*currency.CurrencyPriceList, error)
+}
+
+type service struct {
+	rules           *Rules
+	currencyService pricesFetcher
+	providerName    string
+	officialPair    string
+}
+
+// NewService returns the tax breakdowns of the official dollar quoted as officialPair by the provider.
+func NewService(r *Rules, cs pricesFetcher, providerName string, officialPair string) *service {
+	return &service{rules: r, currencyService: cs, providerName: providerName, officialPair: officialPair}
+}
+
+// Breakdowns applies the rules in force to the current official dollar ask price.
+func (s *service) Breakdowns(ctx context.Context) ([]*Breakdown, error) {
+	priceList, err := s.currencyService.GetLastPrices(ctx, s.providerName)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting official dollar price")
+	}
+
+	official := priceList.Find(s.officialPair)
+	if official == nil || !official.AskPrice.IsPositive() {
+		return nil, ErrNoOfficialPrice
+	}
+
+	return s.rules.Apply(official.AskPrice, time.Now()), nil
+}
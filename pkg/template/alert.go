@@ -0,0 +1,34 @@
+package template
+
+import (
+	"coinbani/pkg/alert"
+	"coinbani/pkg/currency"
+)
+
+const (
+	AlertsTemplate = `
+<strong>Tus alertas</strong>
+{{range .}}
+#{{.ID}} {{.Pair}} en {{.Provider}} {{html .Condition}}{{else}}
+No tienes alertas activas
+{{end}}`
+
+	AlertTriggeredTemplate = `
+<strong>Alerta #{{.Alert.ID}}</strong>
+
+{{.Alert.Pair}} en {{.Alert.Provider}} cotiza a {{.Price.StringFixed 2}} ({{html .Alert.Condition}})
+`
+)
+
+type alertTriggeredData struct {
+	Alert *alert.Alert
+	Price currency.Money
+}
+
+func (e *templateEngine) FormatAlertsMessage(alerts []*alert.Alert) (string, error) {
+	return e.processTemplate(AlertsTemplate, alerts)
+}
+
+func (e *templateEngine) FormatAlertTriggeredMessage(a *alert.Alert, price currency.Money) (string, error) {
+	return e.processTemplate(AlertTriggeredTemplate, &alertTriggeredData{Alert: a, Price: price})
+}
@@ -0,0 +1,43 @@
+package template
+
+import (
+	"strings"
+
+	"coinbani/pkg/arbitrage"
+)
+
+const (
+	ArbitrageTemplate = `
+<strong>Arbitraje</strong>
+{{range .Opportunities}}
+{{.Route}}
+<strong>{{.ProfitPercent.StringSigned 2}}%</strong>
+{{else}}
+No hay oportunidades de arbitraje en este momento
+{{end}}
+<i>Incluye las comisiones de cada proveedor y la de retiro al pasar de uno a otro, ver /comisiones</i>{{if .UnavailableNames}}
+<i>No disponibles: {{.UnavailableNames}}</i>{{end}}
+`
+
+	ArbitrageAlertTemplate = `
+<strong>Oportunidad de arbitraje</strong>
+
+{{.Route}}
+<strong>{{.ProfitPercent.StringSigned 2}}%</strong>
+
+Para desactivar usa /arbitraje alerta off
+`
+)
+
+type arbitrageData struct {
+	*arbitrage.Analysis
+	UnavailableNames string
+}
+
+func (e *templateEngine) FormatArbitrageMessage(a *arbitrage.Analysis) (string, error) {
+	return e.processTemplate(ArbitrageTemplate, &arbitrageData{Analysis: a, UnavailableNames: strings.Join(a.Unavailable, ", ")})
+}
+
+func (e *templateEngine) FormatArbitrageAlertMessage(o *arbitrage.Opportunity) (string, error) {
+	return e.processTemplate(ArbitrageAlertTemplate, o)
+}
@@ -0,0 +1,38 @@
+package template
+
+import (
+	"strings"
+
+	"coinbani/pkg/compare"
+	"coinbani/pkg/currency"
+)
+
+const (
+	ComparisonTemplate = `
+<strong>Mejor precio para {{.Side}} {{.Pair}}</strong>
+<pre>
+{{range $i, $q := .Quotes}}{{if eq $i 0}}» {{else}}  {{end}}{{printf "%-14s %12s" $q.Provider ($q.Price.StringFixed 2)}}{{if $q.Stale}} *{{end}}
+{{end}}</pre>{{with .RunnerUp}}
+{{$.Best.Provider}} es {{$.SpreadValue.StringFixed 2}} ({{$.SpreadPercent.StringFixed 2}}%) mejor que {{.Provider}}{{else}}
+Solo {{.Best.Provider}} cotiza este par{{end}}{{if .HasStale}}
+<i>* datos desactualizados</i>{{end}}{{if .UnavailableNames}}
+<i>No disponibles: {{.UnavailableNames}}</i>{{end}}
+`
+)
+
+type comparisonData struct {
+	*compare.Comparison
+	SpreadValue      currency.Money
+	SpreadPercent    currency.Money
+	HasStale         bool
+	UnavailableNames string
+}
+
+func (e *templateEngine) FormatComparisonMessage(c *compare.Comparison) (string, error) {
+	data := &comparisonData{Comparison: c, UnavailableNames: strings.Join(c.Unavailable, ", ")}
+	data.SpreadValue, data.SpreadPercent = c.Spread()
+	for _, q := range c.Quotes {
+		data.HasStale = data.HasStale || q.Stale
+	}
+	return e.processTemplate(ComparisonTemplate, data)
+}
@@ -0,0 +1,46 @@
+package template
+
+import (
+	"coinbani/pkg/convert"
+	"coinbani/pkg/currency"
+)
+
+const (
+	ConversionTemplate = `
+<strong>{{.Amount}} {{.From}} = {{.Result}} {{.To}}</strong>
+
+Ruta: {{.Route}}
+Tipo de cambio: 1 {{.From}} = {{.Rate}} {{.To}}
+Tipo de cambio: 1 {{.To}} = {{.InverseRate}} {{.From}}
+`
+)
+
+type conversionData struct {
+	Amount      string
+	From        string
+	Result      string
+	To          string
+	Route       string
+	Rate        string
+	InverseRate string
+}
+
+func (e *templateEngine) FormatConversionMessage(c *convert.Conversion) (string, error) {
+	return e.processTemplate(ConversionTemplate, &conversionData{
+		Amount:      formatAmount(c.Amount),
+		From:        c.From,
+		Result:      formatAmount(c.Result),
+		To:          c.To,
+		Route:       c.Route(),
+		Rate:        formatAmount(c.Rate()),
+		InverseRate: formatAmount(currency.MoneyFromInt(1).Div(c.Rate())),
+	})
+}
+
+// formatAmount keeps two decimals, or up to eight for amounts below one such as BTC.
+func formatAmount(m currency.Money) string {
+	if m.LessThan(currency.MoneyFromInt(1)) {
+		return m.Round(8).String()
+	}
+	return m.StringFixed(2)
+}
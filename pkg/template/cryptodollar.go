@@ -0,0 +1,35 @@
+package template
+
+import (
+	"strings"
+
+	"coinbani/pkg/cryptodollar"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CryptoDollarsTemplate = `
+<strong>Dólar cripto</strong>
+
+<pre>
+{{.PricesTable}}
+</pre>
+<i>Precio de un dólar comprando una stablecoin o BTC en pesos y vendiéndola en dólares, el % es la diferencia con el MEP</i>{{if .UnavailableNames}}
+<i>No disponibles: {{.UnavailableNames}}</i>{{end}}
+`
+)
+
+type cryptoDollarsData struct {
+	PricesTable      string
+	UnavailableNames string
+}
+
+func (e *templateEngine) FormatCryptoDollarsMessage(t *cryptodollar.Table) (string, error) {
+	pricesTable, err := e.tableFormatter.FormatPricesTable(t.Prices, false)
+	if err != nil {
+		return "", errors.Wrap(err, "formatting crypto dollars table")
+	}
+
+	return e.processTemplate(CryptoDollarsTemplate, &cryptoDollarsData{PricesTable: pricesTable, UnavailableNames: strings.Join(t.Unavailable, ", ")})
+}
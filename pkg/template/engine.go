@@ -2,7 +2,9 @@ package template
 
 import (
 	"bytes"
+	"math"
 	"text/template"
+	"time"
 
 	"coinbani/pkg/currency"
 
@@ -11,14 +13,31 @@ import (
 
 const (
 	PricesTemplate = `
-<strong>{{.ProviderName}}</strong>
+<strong>{{.ProviderName}}</strong>{{if .Stale}} <i>(datos de hace {{.StaleMinutes}} min)</i>{{end}}
 
 <pre>
 {{.PricesTable}}
-</pre>
+</pre>{{range .Gaps}}
+Brecha {{.Name}}: <strong>{{.Percent.StringFixed 2}}%</strong>{{end}}
+<i>Actualizado: {{.UpdatedAt}}</i>
 `
+
+	AllPricesTemplate = `{{range .}}
+<strong>{{.ProviderName}}</strong>{{if .Stale}} <i>(datos de hace {{.StaleMinutes}} min)</i>{{end}}
+{{if .PricesTable}}
+<pre>
+{{.PricesTable}}
+</pre>{{range .Gaps}}
+Brecha {{.Name}}: <strong>{{.Percent.StringFixed 2}}%</strong>{{end}}
+<i>Actualizado: {{.UpdatedAt}}</i>
+{{else}}
+<i>No disponible en este momento</i>
+{{end}}{{end}}`
 )
 
+// displayLocation is the Argentina time zone, fixed since it has no DST
+var displayLocation = time.FixedZone("ART", -3*60*60)
+
 type templateEngine struct {
 	tableFormatter tableFormatter
 }
@@ -28,16 +47,42 @@ func NewEngine() *templateEngine {
 }
 
 func (e *templateEngine) FormatPricesMessage(priceList *currency.CurrencyPriceList) (string, error) {
-	pricesTable, err := e.tableFormatter.FormatPricesTable(priceList.Prices)
+	pricesTable, err := e.tableFormatter.FormatPricesTable(priceList.Prices, false)
 	if err != nil {
 		return "", errors.Wrap(err, "formatting prices table")
 	}
 
-	data := &priceData{
+	data := newPriceData(priceList)
+	data.PricesTable = pricesTable
+	return e.processTemplate(PricesTemplate, data)
+}
+
+func (e *templateEngine) FormatAllPricesMessage(results []*currency.ProviderPrices) (string, error) {
+	data := make([]*priceData, 0, len(results))
+	for _, r := range results {
+		d := &priceData{ProviderName: r.ProviderName}
+		if r.Err == nil && r.PriceList != nil {
+			d = newPriceData(r.PriceList)
+			pricesTable, err := e.tableFormatter.FormatPricesTable(r.PriceList.Prices, false)
+			if err != nil {
+				return "", errors.Wrapf(err, "formatting %s prices table", r.ProviderName)
+			}
+			d.PricesTable = pricesTable
+		}
+		data = append(data, d)
+	}
+
+	return e.processTemplate(AllPricesTemplate, data)
+}
+
+func newPriceData(priceList *currency.CurrencyPriceList) *priceData {
+	return &priceData{
 		ProviderName: priceList.ProviderName,
-		PricesTable:  pricesTable,
+		Stale:        priceList.IsStale(),
+		StaleMinutes: int(math.Ceil(priceList.Age().Minutes())),
+		UpdatedAt:    priceList.LastUpdate().In(displayLocation).Format("15:04"),
+		Gaps:         priceList.Gaps,
 	}
-	return e.processTemplate(PricesTemplate, data)
 }
 
 func (e *templateEngine) processTemplate(t string, data interface{}) (string, error) {
@@ -0,0 +1,40 @@
+package template
+
+import (
+	"coinbani/cmd/coinbani/options"
+	"coinbani/pkg/currency"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	FeesTemplate = `
+<strong>Comisiones de {{.ProviderName}}</strong>
+{{with .Fees}}{{if .IsZero}}
+No se aplican comisiones a las cotizaciones
+{{else}}
+Tomador: {{.TakerPercent}}% · Spread: {{.SpreadPercent}}%
+Retiro: {{.WithdrawalPercent}}%
+{{end}}{{end}}
+<pre>
+{{.PricesTable}}
+</pre>
+<i>Los precios incluyen la comisión de tomador y el spread, s/c son los precios sin comisiones</i>
+`
+)
+
+type feesData struct {
+	*priceData
+	Fees options.FeeModel
+}
+
+func (e *templateEngine) FormatFeesMessage(priceList *currency.CurrencyPriceList, fees options.FeeModel) (string, error) {
+	pricesTable, err := e.tableFormatter.FormatPricesTable(priceList.Prices, true)
+	if err != nil {
+		return "", errors.Wrap(err, "formatting prices table")
+	}
+
+	data := &feesData{priceData: newPriceData(priceList), Fees: fees}
+	data.PricesTable = pricesTable
+	return e.processTemplate(FeesTemplate, data)
+}
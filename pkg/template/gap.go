@@ -0,0 +1,28 @@
+package template
+
+import (
+	"strings"
+
+	"coinbani/pkg/gap"
+)
+
+const (
+	GapsTemplate = `
+<strong>Brecha cambiaria</strong>
+<pre>
+{{range .Gaps}}{{printf "%-16s %8s%%" .Name (.Percent.StringFixed 2)}}{{if .HasChange}} {{printf "%7s" (.Change.StringSigned 2)}} pp{{end}}
+  {{.Price.StringFixed 2}} / {{.Base.StringFixed 2}}
+{{end}}</pre>
+<i>La variación es en puntos porcentuales respecto de hace 24 horas</i>{{if .UnavailableNames}}
+<i>No disponibles: {{.UnavailableNames}}</i>{{end}}
+`
+)
+
+type gapsData struct {
+	*gap.Report
+	UnavailableNames string
+}
+
+func (e *templateEngine) FormatGapsMessage(r *gap.Report) (string, error) {
+	return e.processTemplate(GapsTemplate, &gapsData{Report: r, UnavailableNames: strings.Join(r.Unavailable, ", ")})
+}
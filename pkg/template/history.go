@@ -0,0 +1,34 @@
+package template
+
+import (
+	"coinbani/pkg/history"
+)
+
+const (
+	HistoryTemplate = `
+<strong>Historial {{.Pair}} ({{.Period}})</strong>
+{{range .Stats}}
+<pre>
+{{.Provider}}
+Apertura   {{.Open.StringFixed 2}}
+Cierre     {{.Close.StringFixed 2}}
+Mínimo     {{.Min.StringFixed 2}}
+Máximo     {{.Max.StringFixed 2}}
+Variación  {{.Change.StringSigned 2}} ({{.ChangePercent.StringSigned 2}}%)
+</pre>{{else}}
+Todavía no hay datos registrados para este período
+{{end}}`
+)
+
+type historyData struct {
+	Pair   string
+	Period string
+	Stats  []*history.Stats
+}
+
+func (e *templateEngine) FormatHistoryMessage(pair string, period string, stats []*history.Stats) (string, error) {
+	if len(stats) > 0 {
+		pair = stats[0].Pair
+	}
+	return e.processTemplate(HistoryTemplate, &historyData{Pair: pair, Period: period, Stats: stats})
+}
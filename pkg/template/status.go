@@ -0,0 +1,44 @@
+package template
+
+import (
+	"coinbani/pkg/client"
+)
+
+const (
+	StatusTemplate = `
+<strong>Estado de servicios</strong>
+{{range .}}
+{{.Host}}: {{.State}}{{if .Failures}} ({{.Failures}} fallas){{end}}{{if .OpenedAt}}, desde las {{.OpenedAt}}{{end}}{{else}}
+Todavía no se consultó ningún servicio
+{{end}}`
+)
+
+var breakerStateNames = map[client.BreakerState]string{
+	client.BreakerClosed:   "OK",
+	client.BreakerOpen:     "caído",
+	client.BreakerHalfOpen: "recuperándose",
+}
+
+type breakerStatusData struct {
+	Host     string
+	State    string
+	Failures int
+	OpenedAt string
+}
+
+func (e *templateEngine) FormatStatusMessage(statuses []client.BreakerStatus) (string, error) {
+	data := make([]*breakerStatusData, 0, len(statuses))
+	for _, s := range statuses {
+		d := &breakerStatusData{
+			Host:     s.Host,
+			State:    breakerStateNames[s.State],
+			Failures: s.Failures,
+		}
+		if s.State != client.BreakerClosed {
+			d.OpenedAt = s.OpenedAt.In(displayLocation).Format("15:04")
+		}
+		data = append(data, d)
+	}
+
+	return e.processTemplate(StatusTemplate, data)
+}
@@ -11,10 +11,14 @@ import (
 type priceData struct {
 	ProviderName string
 	PricesTable  string
+	Stale        bool
+	StaleMinutes int
+	UpdatedAt    string
+	Gaps         []*currency.Gap
 }
 
 type tableFormatter interface {
-	FormatPricesTable(prices []*currency.CurrencyPrice) (content string, err error)
+	FormatPricesTable(prices []*currency.CurrencyPrice, withRaw bool) (content string, err error)
 }
 
 type simpleTableFormatter struct {
@@ -24,7 +28,8 @@ func NewTableFormatter() *simpleTableFormatter {
 	return &simpleTableFormatter{}
 }
 
-func (t *simpleTableFormatter) FormatPricesTable(prices []*currency.CurrencyPrice) (content string, err error) {
+// FormatPricesTable renders the prices after fees, withRaw adds the quotes before the provider fees.
+func (t *simpleTableFormatter) FormatPricesTable(prices []*currency.CurrencyPrice, withRaw bool) (content string, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			var ok bool
@@ -46,12 +51,24 @@ func (t *simpleTableFormatter) FormatPricesTable(prices []*currency.CurrencyPric
 			{Align: simpletable.AlignLeft, Text: "Venta"},
 		},
 	}
+	if withRaw {
+		table.Header.Cells = append(table.Header.Cells,
+			&simpletable.Cell{Align: simpletable.AlignLeft, Text: "Compra s/c"},
+			&simpletable.Cell{Align: simpletable.AlignLeft, Text: "Venta s/c"},
+		)
+	}
 
 	for _, price := range prices {
 		r := []*simpletable.Cell{
 			{Align: simpletable.AlignLeft, Text: price.Desc},
-			{Align: simpletable.AlignLeft, Text: fmt.Sprintf("%.2f", price.BidPrice)},
-			{Align: simpletable.AlignLeft, Text: fmt.Sprintf("%.2f", price.AskPrice)},
+			{Align: simpletable.AlignLeft, Text: formatPrice(price.BidPrice)},
+			{Align: simpletable.AlignLeft, Text: formatPrice(price.AskPrice)},
+		}
+		if withRaw {
+			r = append(r,
+				&simpletable.Cell{Align: simpletable.AlignLeft, Text: formatPrice(price.RawBidPrice)},
+				&simpletable.Cell{Align: simpletable.AlignLeft, Text: formatPrice(price.RawAskPrice)},
+			)
 		}
 
 		if price.PercentChange != "" {
@@ -69,3 +86,11 @@ func (t *simpleTableFormatter) FormatPricesTable(prices []*currency.CurrencyPric
 	table.SetStyle(simpletable.StyleCompactLite)
 	return table.String(), nil
 }
+
+// formatPrice shows a dash for the sides a pair is not quoted on, like the taxed dollars that can only be bought.
+func formatPrice(p currency.Money) string {
+	if p.IsZero() {
+		return "-"
+	}
+	return p.StringFixed(2)
+}
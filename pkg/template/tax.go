@@ -0,0 +1,23 @@
+package template
+
+import (
+	"coinbani/pkg/tax"
+)
+
+const (
+	TaxesTemplate = `
+<strong>Impuestos al dólar</strong>
+{{range .}}
+<pre>
+{{.Row}}
+{{printf "%-30s %10s" "Oficial" (.Base.StringFixed 2)}}
+{{range .Taxes}}{{printf "%-30s %10s" (printf "%s %s%%" .Name .Percent.String) (.Amount.StringFixed 2)}}
+{{end}}{{printf "%-30s %10s" "Total" (.Total.StringFixed 2)}}
+</pre>{{else}}
+No hay dólares con impuestos configurados
+{{end}}`
+)
+
+func (e *templateEngine) FormatTaxesMessage(breakdowns []*tax.Breakdown) (string, error) {
+	return e.processTemplate(TaxesTemplate, breakdowns)
+}